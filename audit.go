@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: AUDIT
+// ════════════════════════════════════════════════════════════════
+
+const (
+	severityCritical = "critical"
+	severityHigh     = "high"
+	severityMedium   = "medium"
+	severityLow      = "low"
+)
+
+var severityRank = map[string]int{severityCritical: 0, severityHigh: 1, severityMedium: 2, severityLow: 3}
+
+const (
+	defaultPostgresPassword = "secure_password_123"
+	minWebAPITokenLen       = 32
+	certWarnDays            = 21
+	baseImageMaxAgeDays     = 90
+)
+
+// auditFinding — результат проверки; поля JSON стабильны для внешних инструментов
+type auditFinding struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail,omitempty"`
+	Fix      string `json:"fix,omitempty"`
+}
+
+// auditSecrets — пароль БД и токен web API из .env
+func auditSecrets(cfg *Config) []auditFinding {
+	var findings []auditFinding
+	if !externalPostgres(cfg) && (cfg.PostgresPassword == "" || cfg.PostgresPassword == defaultPostgresPassword) {
+		findings = append(findings, auditFinding{
+			ID: "default_postgres_password", Severity: severityCritical,
+			Title:  "PostgreSQL использует пароль по умолчанию " + defaultPostgresPassword,
+			Detail: "POSTGRES_PASSWORD пуст или равен значению по умолчанию из compose",
+			Fix:    "bot rotate-secrets --postgres",
+		})
+	}
+	if cfg.WebAPIEnabled != "false" && len(cfg.WebAPIDefaultToken) < minWebAPITokenLen {
+		findings = append(findings, auditFinding{
+			ID: "weak_webapi_token", Severity: severityHigh,
+			Title:  fmt.Sprintf("WEB_API_DEFAULT_TOKEN короче %d символов", minWebAPITokenLen),
+			Detail: fmt.Sprintf("длина: %d", len(cfg.WebAPIDefaultToken)),
+			Fix:    "bot rotate-secrets --webapi",
+		})
+	}
+	return findings
+}
+
+// auditFileModes — .env и бэкапы не должны читаться группой и остальными
+func auditFileModes(installDir string) []auditFinding {
+	var findings []auditFinding
+	envPath := filepath.Join(installDir, ".env")
+	if info, err := os.Stat(envPath); err == nil && info.Mode().Perm()&0077 != 0 {
+		findings = append(findings, auditFinding{
+			ID: "env_permissions", Severity: severityHigh,
+			Title:  fmt.Sprintf(".env доступен не только владельцу (%04o)", info.Mode().Perm()),
+			Detail: envPath,
+			Fix:    "bot doctor --fix-permissions",
+		})
+	}
+
+	var exposed []string
+	check := func(path string, info os.FileInfo) {
+		if info.Mode().IsRegular() && info.Mode().Perm()&0044 != 0 {
+			exposed = append(exposed, path)
+		}
+	}
+	filepath.Walk(filepath.Join(installDir, "data", "backups"), func(path string, info os.FileInfo, err error) error {
+		if err == nil {
+			check(path, info)
+		}
+		return nil
+	})
+	for _, pattern := range []string{filepath.Join(installDir, ".env.backup_*"), "/root/bedolaga_backup_*.tar.gz"} {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil {
+				check(m, info)
+			}
+		}
+	}
+	if len(exposed) > 0 {
+		detail := strings.Join(exposed, ", ")
+		if len(exposed) > 3 {
+			detail = strings.Join(exposed[:3], ", ") + fmt.Sprintf(" и ещё %d", len(exposed)-3)
+		}
+		findings = append(findings, auditFinding{
+			ID: "backup_permissions", Severity: severityHigh,
+			Title:  fmt.Sprintf("Бэкапы доступны на чтение группе или всем (%d файлов)", len(exposed)),
+			Detail: detail,
+			Fix:    "bot doctor --fix-permissions && chmod 600 /root/bedolaga_backup_*.tar.gz",
+		})
+	}
+	return findings
+}
+
+// publicBinding возвращает привязку порта из вывода docker port, открытую на всех интерфейсах
+func publicBinding(dockerPort string) string {
+	for _, binding := range strings.Split(dockerPort, "\n") {
+		if strings.HasPrefix(binding, "0.0.0.0:") || strings.HasPrefix(binding, "[::]:") || strings.HasPrefix(binding, ":::") {
+			return binding
+		}
+	}
+	return ""
+}
+
+func auditWebAPIExposure(cfg *Config) []auditFinding {
+	out, _ := runShellSilent(fmt.Sprintf("docker port remnawave_bot %s/tcp 2>/dev/null", webAPIPort(cfg)))
+	if b := publicBinding(out); b != "" {
+		return []auditFinding{{
+			ID: "webapi_exposed", Severity: severityMedium,
+			Title:  "Порт web API опубликован на всех интерфейсах",
+			Detail: b + " — доступен в обход обратного прокси",
+			Fix:    webAPIExposureFix(cfg),
+		}}
+	}
+	return nil
+}
+
+func auditFirewall() []auditFinding {
+	if commandExists("ufw") {
+		if out, _ := runShellSilent("ufw status 2>/dev/null | head -1"); strings.Contains(out, "active") && !strings.Contains(out, "inactive") {
+			return nil
+		}
+		return []auditFinding{{ID: "firewall_disabled", Severity: severityMedium, Title: "UFW отключён",
+			Fix: "ufw allow 22/tcp && ufw allow 80/tcp && ufw allow 443/tcp && ufw enable"}}
+	}
+	if commandExists("firewall-cmd") {
+		if out, _ := runShellSilent("firewall-cmd --state 2>/dev/null"); out == "running" {
+			return nil
+		}
+		return []auditFinding{{ID: "firewall_disabled", Severity: severityMedium, Title: "firewalld не запущен",
+			Fix: "systemctl enable --now firewalld"}}
+	}
+	return []auditFinding{{ID: "firewall_disabled", Severity: severityMedium, Title: "Firewall не установлен",
+		Fix: pkgManager().installCmd("ufw")}}
+}
+
+// auditSSHConfig — вывод sshd -T: порт 22 вместе с входом по паролю
+func auditSSHConfig(sshdT string) []auditFinding {
+	settings := map[string]string{}
+	ports := map[string]bool{}
+	for _, line := range strings.Split(sshdT, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if fields[0] == "port" {
+			ports[fields[1]] = true
+		}
+		settings[fields[0]] = fields[1]
+	}
+	if settings["passwordauthentication"] != "yes" {
+		return nil
+	}
+	f := auditFinding{
+		ID: "ssh_password_auth", Severity: severityMedium,
+		Title: "SSH разрешает вход по паролю",
+		Fix:   "настройте ключи, затем PasswordAuthentication no в /etc/ssh/sshd_config и systemctl reload ssh",
+	}
+	if ports["22"] {
+		f.Title += " на стандартном порту 22"
+		f.Severity = severityHigh
+	}
+	if settings["permitrootlogin"] == "yes" {
+		f.Detail = "PermitRootLogin yes"
+	}
+	return []auditFinding{f}
+}
+
+// auditCertExpiry — срок действия сертификата домена
+func auditCertExpiry(domain string, notAfter, now time.Time) []auditFinding {
+	days := int(notAfter.Sub(now).Hours() / 24)
+	f := auditFinding{
+		ID:     "certificate_expiry",
+		Detail: fmt.Sprintf("%s: действует до %s", domain, notAfter.Format("2006-01-02")),
+		Fix:    "certbot renew --cert-name " + domain + " (для Caddy: docker restart remnawave_caddy)",
+	}
+	switch {
+	case !notAfter.After(now):
+		f.Severity, f.Title = severityCritical, "Сертификат "+domain+" истёк"
+	case days < certWarnDays:
+		f.Severity, f.Title = severityHigh, fmt.Sprintf("Сертификат %s истекает через %d дн.", domain, days)
+	default:
+		return nil
+	}
+	return []auditFinding{f}
+}
+
+func certNotAfter(domain string) (time.Time, bool) {
+	cmd := fmt.Sprintf("openssl x509 -enddate -noout -in /etc/letsencrypt/live/%s/cert.pem 2>/dev/null", domain)
+	out, err := runShellSilent(cmd)
+	if err != nil || out == "" {
+		cmd = fmt.Sprintf("echo | timeout 10 openssl s_client -servername %s -connect %s:443 2>/dev/null | openssl x509 -enddate -noout 2>/dev/null", domain, domain)
+		out, _ = runShellSilent(cmd)
+	}
+	t, err := time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimPrefix(out, "notAfter="))
+	return t, err == nil
+}
+
+func auditContainers() []auditFinding {
+	var findings []auditFinding
+	for _, c := range []string{"remnawave_bot", "remnawave_bot_db", "remnawave_bot_redis", "remnawave_caddy"} {
+		if out, _ := runShellSilent(fmt.Sprintf("docker inspect --format '{{.HostConfig.Privileged}}' %s 2>/dev/null", c)); out == "true" {
+			findings = append(findings, auditFinding{
+				ID: "privileged_container", Severity: severityHigh,
+				Title: "Контейнер " + c + " запущен в privileged-режиме",
+				Fix:   "уберите privileged: true из " + composeOverrideFile + " и выполните bot restart",
+			})
+		}
+	}
+	return findings
+}
+
+// auditBaseImages — локальные базовые образы старше baseImageMaxAgeDays
+func auditBaseImages(now time.Time) []auditFinding {
+	var stale []string
+	for _, img := range bundleBaseImages {
+		out, err := runShellSilent(fmt.Sprintf("docker image inspect --format '{{.Created}}' %s 2>/dev/null", img))
+		if err != nil || out == "" {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339Nano, out)
+		if err == nil && now.Sub(created) > baseImageMaxAgeDays*24*time.Hour {
+			stale = append(stale, fmt.Sprintf("%s (%s)", img, created.Format("2006-01-02")))
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return []auditFinding{{
+		ID: "outdated_base_images", Severity: severityLow,
+		Title:  fmt.Sprintf("Базовые образы старше %d дней", baseImageMaxAgeDays),
+		Detail: strings.Join(stale, ", "),
+		Fix:    "docker pull " + strings.Join(bundleBaseImages, " && docker pull ") + " && bot restart",
+	}}
+}
+
+// runAudit выполняет все проверки; результаты отсортированы по важности
+func runAudit(installDir string) []auditFinding {
+	cfg, _ := loadInstallConfig(installDir)
+	now := time.Now()
+
+	findings := auditFileModes(installDir)
+	findings = append(findings, auditSecrets(cfg)...)
+	findings = append(findings, auditWebAPIExposure(cfg)...)
+	findings = append(findings, auditFirewall()...)
+	if out, err := runShellSilent("sshd -T 2>/dev/null"); err == nil {
+		findings = append(findings, auditSSHConfig(out)...)
+	}
+	for _, domain := range []string{cfg.WebhookDomain, cfg.MiniappDomain} {
+		if domain == "" {
+			continue
+		}
+		if notAfter, ok := certNotAfter(domain); ok {
+			findings = append(findings, auditCertExpiry(domain, notAfter, now)...)
+		}
+	}
+	findings = append(findings, auditContainers()...)
+	findings = append(findings, auditBaseImages(now)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return severityRank[findings[i].Severity] < severityRank[findings[j].Severity]
+	})
+	return findings
+}
+
+func manageAudit(installDir string, args []string) {
+	findings := runAudit(installDir)
+	failed := len(findings) > 0 && severityRank[findings[0].Severity] <= severityRank[severityHigh]
+
+	if containsString(args, "--json") {
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"install_dir": installDir,
+			"generated":   time.Now().UTC().Format(time.RFC3339),
+			"findings":    append([]auditFinding{}, findings...),
+		}, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		fmt.Println()
+		fmt.Println(ui.AccentBar.Render("  АУДИТ БЕЗОПАСНОСТИ"))
+		fmt.Println()
+		if len(findings) == 0 {
+			ui.PrintSuccess("Проблем не найдено")
+		}
+		for _, f := range findings {
+			line := fmt.Sprintf("[%s] %s", strings.ToUpper(f.Severity), f.Title)
+			switch f.Severity {
+			case severityCritical, severityHigh:
+				ui.PrintError(line)
+			case severityMedium:
+				ui.PrintWarning(line)
+			default:
+				ui.PrintInfo(line)
+			}
+			if f.Detail != "" {
+				ui.PrintDim(f.Detail)
+			}
+			if f.Fix != "" {
+				ui.PrintDim("Исправить: " + f.Fix)
+			}
+		}
+		fmt.Println()
+	}
+	if failed && len(os.Args) > 2 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// OFFLINE BUNDLE (air-gapped install)
+// ════════════════════════════════════════════════════════════════
+//
+// Структура архива:
+//   manifest.json       — версия, образы, образ бота
+//   bedolaga_installer  — сам установщик
+//   repo/               — снимок репозитория бота (с .git)
+//   images.tar          — docker save всех образов
+
+const (
+	bundleManifestFile = "manifest.json"
+	bundleRepoDir      = "repo"
+	bundleImagesFile   = "images.tar"
+	bundleInstaller    = "bedolaga_installer"
+	bundleBotImage     = "bedolaga/remnawave-bot:offline"
+)
+
+var bundleBaseImages = []string{"postgres:15-alpine", "redis:7-alpine", "caddy:2-alpine"}
+
+type bundleManifest struct {
+	InstallerVersion string    `json:"installer_version"`
+	Created          time.Time `json:"created"`
+	RepoCommit       string    `json:"repo_commit"`
+	BotImage         string    `json:"bot_image"`
+	Images           []string  `json:"images"`
+}
+
+const bundleUsage = "Использование: bedolaga_installer bundle create [-o файл.tar.gz] [--image образ:тег]"
+
+func bundleCommand() {
+	if len(os.Args) < 3 || os.Args[2] != "create" {
+		ui.PrintError(bundleUsage)
+		os.Exit(1)
+	}
+	output := fmt.Sprintf("bedolaga-bundle-%s.tar.gz", time.Now().Format("20060102"))
+	image := ""
+	args := os.Args[3:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-o", "--output":
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				output = args[i+1]
+				i++
+			} else {
+				ui.PrintError("Укажите файл: -o файл.tar.gz")
+				os.Exit(1)
+			}
+		case "--image":
+			if i+1 < len(args) && validImageRef(args[i+1]) {
+				image = args[i+1]
+				i++
+			} else {
+				ui.PrintError("Укажите образ: --image registry/имя:тег")
+				os.Exit(1)
+			}
+		default:
+			ui.PrintError("Неизвестный аргумент: " + args[i])
+			ui.PrintDim(bundleUsage)
+			os.Exit(1)
+		}
+	}
+	output, _ = filepath.Abs(output)
+
+	ui.PrintBanner(appVersion)
+	if err := createBundle(output, image); err != nil {
+		ui.PrintError("Ошибка создания бандла: " + err.Error())
+		os.Exit(1)
+	}
+	size, _ := runShellSilent(fmt.Sprintf("du -h %q | awk '{print $1}'", output))
+	ui.PrintSuccessBox(ui.SuccessStyle.Render("Бандл создан: "+output) + "\n" +
+		ui.DimStyle.Render("Размер: "+size+"\nУстановка: bedolaga_installer install --offline "+filepath.Base(output)))
+}
+
+func createBundle(output, image string) error {
+	if !commandExists("docker") || !commandExists("git") {
+		return fmt.Errorf("для создания бандла нужны docker и git")
+	}
+	work, err := os.MkdirTemp("", "bedolaga-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(work)
+
+	repo := filepath.Join(work, bundleRepoDir)
+	err = ui.RunWithSpinner("Клонирование репозитория бота...", func() error {
+		out, err := runCmdSilent("git", "clone", "--depth", "1", repoURL, repo)
+		if err != nil {
+			return fmt.Errorf("git clone: %s", lastLine(out))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	commit, _ := runShellSilent(fmt.Sprintf("git -C %s rev-parse HEAD", repo))
+
+	botImage := image
+	err = ui.RunWithSpinner("Подготовка образа бота...", func() error {
+		if botImage != "" {
+			out, err := runShellSilent(fmt.Sprintf("docker pull %s 2>&1", botImage))
+			if err != nil {
+				return fmt.Errorf("docker pull %s: %s", botImage, lastLine(out))
+			}
+			return nil
+		}
+		botImage = bundleBotImage
+		out, err := runShellSilent(fmt.Sprintf("docker build -t %s %s 2>&1", botImage, repo))
+		if err != nil {
+			return fmt.Errorf("docker build: %s", lastLine(out))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	images := append(append([]string{}, bundleBaseImages...), botImage)
+	err = ui.RunWithSpinner("Сохранение Docker-образов...", func() error {
+		for _, img := range bundleBaseImages {
+			if out, err := runShellSilent(fmt.Sprintf("docker pull %s 2>&1", img)); err != nil {
+				return fmt.Errorf("docker pull %s: %s", img, lastLine(out))
+			}
+		}
+		out, err := runShellSilent(fmt.Sprintf("docker save -o %s %s 2>&1", filepath.Join(work, bundleImagesFile), strings.Join(images, " ")))
+		if err != nil {
+			return fmt.Errorf("docker save: %s", lastLine(out))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if out, err := runCmdSilent("cp", self, filepath.Join(work, bundleInstaller)); err != nil {
+		return fmt.Errorf("копирование установщика: %s", out)
+	}
+
+	manifest := bundleManifest{
+		InstallerVersion: appVersion,
+		Created:          time.Now().UTC(),
+		RepoCommit:       commit,
+		BotImage:         botImage,
+		Images:           images,
+	}
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := os.WriteFile(filepath.Join(work, bundleManifestFile), data, 0644); err != nil {
+		return err
+	}
+
+	return ui.RunWithSpinner("Упаковка архива...", func() error {
+		out, err := runShellSilent(fmt.Sprintf("tar -czf %q -C %q .", output, work))
+		if err != nil {
+			return fmt.Errorf("tar: %s", lastLine(out))
+		}
+		return nil
+	})
+}
+
+// extractBundle распаковывает бандл во временный каталог и читает манифест
+func extractBundle(path string) (string, *bundleManifest, error) {
+	if !fileExists(path) {
+		return "", nil, fmt.Errorf("файл не найден: %s", path)
+	}
+	dir, err := os.MkdirTemp("", "bedolaga-offline-")
+	if err != nil {
+		return "", nil, err
+	}
+	if out, err := runShellSilent(fmt.Sprintf("tar -xf %q -C %q", path, dir)); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("tar: %s", lastLine(out))
+	}
+	manifest, err := readBundleManifest(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+	return dir, manifest, nil
+}
+
+func readBundleManifest(dir string) (*bundleManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("в архиве нет %s — это не бандл bedolaga_installer", bundleManifestFile)
+	}
+	var m bundleManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", bundleManifestFile, err)
+	}
+	if m.BotImage == "" || !dirExists(filepath.Join(dir, bundleRepoDir)) {
+		return nil, fmt.Errorf("бандл неполный: нет образа бота или снимка репозитория")
+	}
+	return &m, nil
+}
+
+// prepareOfflineInstall распаковывает бандл для install --offline
+func prepareOfflineInstall(cfg *Config, path string) {
+	dir, manifest, err := extractBundle(path)
+	if err != nil {
+		globalProgress.fail("Офлайн-бандл: " + err.Error())
+		os.Exit(1)
+	}
+	cfg.Offline = true
+	cfg.OfflineBundleDir = dir
+	cfg.BotImage = manifest.BotImage
+	globalProgress.info(fmt.Sprintf("Офлайн-бандл от %s (установщик v%s, коммит %.8s)",
+		manifest.Created.Format("2006-01-02"), manifest.InstallerVersion, manifest.RepoCommit))
+}
+
+// loadBundleImages — docker load образов из бандла вместо скачивания
+func loadBundleImages(cfg *Config) {
+	if !commandExists("docker") {
+		globalProgress.fail("Docker не установлен — в офлайн-режиме установите Docker заранее")
+		os.Exit(1)
+	}
+	if _, err := runShellSilent("docker compose version 2>/dev/null"); err != nil {
+		globalProgress.fail("Docker Compose не найден — в офлайн-режиме установите docker-compose-plugin заранее")
+		os.Exit(1)
+	}
+	var out string
+	err := ui.RunWithSpinner("Загрузка Docker-образов из бандла...", func() error {
+		var err error
+		out, err = runShellSilent(fmt.Sprintf("docker load -i %s 2>&1", filepath.Join(cfg.OfflineBundleDir, bundleImagesFile)))
+		return err
+	})
+	if err != nil {
+		globalProgress.fail("docker load: " + lastLine(out))
+		os.Exit(1)
+	}
+	globalProgress.done("Образы загружены из бандла")
+}
+
+// cloneFromBundle создаёт или обновляет каталог установки из снимка репозитория
+func cloneFromBundle(cfg *Config) error {
+	snapshot := filepath.Join(cfg.OfflineBundleDir, bundleRepoDir)
+	if dirExists(cfg.InstallDir) {
+		cmd := fmt.Sprintf("cd %s && git checkout -- docker-compose.yml docker-compose.local.yml 2>/dev/null; git fetch --update-shallow %s HEAD 2>&1 && git reset --keep FETCH_HEAD 2>&1", cfg.InstallDir, snapshot)
+		if out, err := runShellSilent(cmd); err != nil {
+			return fmt.Errorf("%s", lastLine(out))
+		}
+		return nil
+	}
+	if out, err := runCmdSilent("git", "clone", snapshot, cfg.InstallDir); err != nil {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	runCmdSilent("git", "-C", cfg.InstallDir, "remote", "set-url", "origin", repoURL)
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: CLEANUP
+// ════════════════════════════════════════════════════════════════
+
+// keepBackups — сколько последних бэкапов (и копий .env) оставлять
+const keepBackups = 5
+
+// cleanupItem — файл или каталог, который можно удалить
+type cleanupItem struct {
+	Path   string
+	Size   int64
+	Reason string
+}
+
+var rotatedLogRe = regexp.MustCompile(`(\.gz|\.\d+|\.log\.\d+.*)$`)
+
+func pathSize(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// olderThanNewest возвращает пути, кроме keep самых новых (по имени — в них дата)
+func olderThanNewest(paths []string, keep int) []string {
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	if len(paths) <= keep {
+		return nil
+	}
+	return paths[keep:]
+}
+
+// cleanupCandidates — старые бэкапы, копии .env и ротированные логи приложения
+func cleanupCandidates(installDir string) []cleanupItem {
+	var items []cleanupItem
+
+	backups, _ := filepath.Glob(filepath.Join(installDir, "data", "backups", "*"))
+	var backupDirs []string
+	for _, b := range backups {
+		if dirExists(b) {
+			backupDirs = append(backupDirs, b)
+		}
+	}
+	for _, p := range olderThanNewest(backupDirs, keepBackups) {
+		items = append(items, cleanupItem{p, pathSize(p), "старый бэкап"})
+	}
+
+	envCopies, _ := filepath.Glob(filepath.Join(installDir, ".env.backup_*"))
+	for _, p := range olderThanNewest(envCopies, keepBackups) {
+		items = append(items, cleanupItem{p, pathSize(p), "копия .env"})
+	}
+
+	filepath.Walk(filepath.Join(installDir, "logs"), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && rotatedLogRe.MatchString(info.Name()) {
+			items = append(items, cleanupItem{path, info.Size(), "ротированный лог"})
+		}
+		return nil
+	})
+	return items
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d Б", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %sБ", float64(n)/float64(div), []string{"К", "М", "Г", "Т"}[exp])
+}
+
+// dockerReclaimable — строка Reclaimable из docker system df для типа (Images, Build Cache)
+func dockerReclaimable(kind string) string {
+	out, _ := runShellSilent("docker system df --format '{{.Type}}\t{{.Reclaimable}}' 2>/dev/null")
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) == 2 && parts[0] == kind {
+			return parts[1]
+		}
+	}
+	return "?"
+}
+
+func manageCleanup(installDir string, args []string) {
+	assumeYes := containsString(args, "--yes") || containsString(args, "-y")
+
+	fmt.Println()
+	fmt.Println(ui.AccentBar.Render("  ОЧИСТКА"))
+	fmt.Println()
+
+	dangling, _ := runShellSilent("docker images -f dangling=true -q 2>/dev/null | wc -l")
+	ui.PrintInfo(fmt.Sprintf("Docker: неиспользуемых (dangling) образов — %s", strings.TrimSpace(dangling)))
+	ui.PrintDim("Освобождаемо образами: " + dockerReclaimable("Images") + ", кэшем сборки: " + dockerReclaimable("Build Cache"))
+
+	items := cleanupCandidates(installDir)
+	var total int64
+	for _, it := range items {
+		total += it.Size
+		rel, _ := filepath.Rel(installDir, it.Path)
+		ui.PrintDim(fmt.Sprintf("%-10s %-18s %s", formatBytes(it.Size), it.Reason, rel))
+	}
+	ui.PrintInfo(fmt.Sprintf("Файлы: %d шт., %s (хранятся последние %d бэкапов)", len(items), formatBytes(total), keepBackups))
+	fmt.Println()
+
+	if !assumeYes && !ui.ConfirmPrompt("Выполнить очистку?", false) {
+		ui.PrintSuccess("Отменено")
+		return
+	}
+
+	ui.RunWithSpinner("Очистка Docker...", func() error {
+		runShellSilent("docker image prune -f 2>/dev/null")
+		runShellSilent("docker builder prune -f 2>/dev/null")
+		return nil
+	})
+	removed := 0
+	for _, it := range items {
+		if os.RemoveAll(it.Path) == nil {
+			removed++
+		}
+	}
+	ui.PrintSuccess(fmt.Sprintf("Удалено файлов: %d (%s), Docker: dangling-образы и кэш сборки", removed, formatBytes(total)))
+}
@@ -17,6 +17,22 @@ func installWizard() {
 	ui.PrintBanner(appVersion)
 	checkRoot()
 
+	// install [--offline bundle.tar.gz] [--proxy URL] [--registry-mirror URL] ...
+	cfg := &Config{}
+	args, err := parseNetworkArgs(cfg, os.Args[2:])
+	if err != nil {
+		ui.PrintError(err.Error())
+		os.Exit(1)
+	}
+	offlineBundle := ""
+	if len(args) > 0 && args[0] == "--offline" {
+		if len(args) < 2 {
+			ui.PrintError("Укажите бандл: bedolaga_installer install --offline bundle.tar.gz")
+			os.Exit(1)
+		}
+		offlineBundle = args[1]
+	}
+
 	ui.PrintBox("📋 Перед началом",
 		ui.InfoStyle.Render("Убедитесь, что у вас есть:")+"\n\n"+
 			ui.HighlightStyle.Render("  1. ")+"BOT_TOKEN от @BotFather\n"+
@@ -28,20 +44,35 @@ func installWizard() {
 		os.Exit(0)
 	}
 
-	cfg := &Config{}
+	if offlineBundle != "" {
+		prepareOfflineInstall(cfg, offlineBundle)
+		defer os.RemoveAll(cfg.OfflineBundleDir)
+	}
+	selectNetworkSettings(cfg)
+	applyNetworkEnv(cfg)
 
 	// 1. System
 	globalProgress.advance("Проверка системы")
 	detectOS()
+	preflightStep()
 
 	// 2. Packages
 	globalProgress.advance("Установка пакетов")
-	updateSystem()
-	installBasePackages()
+	if cfg.Offline {
+		globalProgress.info("Офлайн-режим: обновление системы и пакетов пропущено")
+	} else {
+		configureApt(cfg)
+		updateSystem()
+		installBasePackages()
+	}
 
 	// 3. Docker
 	globalProgress.advance("Настройка Docker")
-	installDocker()
+	if cfg.Offline {
+		loadBundleImages(cfg)
+	} else {
+		installDocker(cfg)
+	}
 
 	// 4. Install dir
 	globalProgress.advance("Каталог установки")
@@ -70,14 +101,7 @@ func installWizard() {
 
 	// 10. Reverse proxy
 	globalProgress.advance("Обратный прокси")
-	switch cfg.ReverseProxyType {
-	case "nginx_system":
-		setupNginxSystem(cfg)
-	case "nginx_panel":
-		setupNginxPanel(cfg)
-	case "caddy":
-		setupCaddy(cfg)
-	}
+	applyReverseProxy(cfg)
 	setupSSL(cfg)
 
 	// 11. Docker start
@@ -87,6 +111,7 @@ func installWizard() {
 
 	// 12. Finish
 	globalProgress.advance("Завершение")
+	setupLogRotation(cfg)
 	createManagementScript(cfg)
 	printFinalInfo(cfg)
 
@@ -98,7 +123,7 @@ func installWizard() {
 			}
 			allowExit = true
 			// Используем exec для замены текущего процесса
-			cmd := exec.Command("bash", "-c", fmt.Sprintf("cd %s && docker compose -f %s logs --tail=150 -f bot", cfg.InstallDir, composeFile))
+			cmd := exec.Command("bash", "-c", composeCmd(cfg.InstallDir, composeFile)+" logs --tail=150 -f bot")
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 			cmd.Stdin = os.Stdin
@@ -132,6 +157,23 @@ func detectComposeFile(installDir string) string {
 	return "docker-compose.yml"
 }
 
+// composeOverrideFile — пользовательские дополнения к compose, установщик его не трогает
+const composeOverrideFile = "docker-compose.override.yml"
+
+// composeFileArgs возвращает флаги -f для основного compose-файла и override (если есть)
+func composeFileArgs(installDir, composeFile string) string {
+	args := "-f " + composeFile
+	if fileExists(filepath.Join(installDir, composeOverrideFile)) {
+		args += " -f " + composeOverrideFile
+	}
+	return args
+}
+
+// composeCmd — префикс "cd <dir> && docker compose -f ..." для команд над стеком бота
+func composeCmd(installDir, composeFile string) string {
+	return fmt.Sprintf("cd %s && docker compose %s", installDir, composeFileArgs(installDir, composeFile))
+}
+
 func updateBot() {
 	ui.PrintBanner(appVersion)
 	installDir := findInstallDir()
@@ -148,13 +190,63 @@ func updateBot() {
 
 	runShellSilent(fmt.Sprintf(`cd %s && cp .env ".env.backup_$(date +%%Y%%m%%d_%%H%%M%%S)" 2>/dev/null || true`, installDir))
 
-	ui.RunWithSpinner("Загрузка последнего кода...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && git pull origin main", installDir))
-		return err
-	})
+	if err := applyUpdateImageArgs(installDir, os.Args[2:]); err != nil {
+		ui.PrintError(err.Error())
+		os.Exit(1)
+	}
 
+	if err := ui.RunWithSpinner("Загрузка последнего кода...", func() error {
+		return pullBotRepo(installDir)
+	}); err != nil {
+		ui.PrintWarning(err.Error())
+	}
+
+	cfg, _ := loadInstallConfig(installDir)
+	composeFile = detectComposeFile(installDir)
 	ui.PrintInfo("Пересборка и перезапуск...")
-	runShell(fmt.Sprintf("cd %s && docker compose -f %s down && docker compose -f %s up -d --build && docker compose -f %s logs -f -t", installDir, composeFile, composeFile, composeFile))
+	fellBack, err := updateBotStack(cfg, composeFile)
+	if fellBack {
+		ui.PrintWarning("Образ " + cfg.BotImage + " недоступен — бот собран из исходников")
+	}
+	if err != nil {
+		ui.PrintError("Ошибка запуска контейнеров: " + err.Error())
+	}
+	allowExit = true
+	runShell(composeCmd(installDir, composeFile) + " logs -f -t")
+}
+
+// updateBotStack перезапускает стек после git pull: при сборке из исходников
+// контейнеры сначала останавливаются, готовый образ скачивается до остановки
+func updateBotStack(cfg *Config, composeFile string) (bool, error) {
+	if cfg.BotImage == "" {
+		runShellSilent(composeCmd(cfg.InstallDir, composeFile) + " down 2>&1")
+	}
+	return upBotStack(cfg, composeFile)
+}
+
+// applyUpdateImageArgs обрабатывает флаги обновления:
+// --image <ref> — перейти на готовый образ, --build — на сборку из исходников
+func applyUpdateImageArgs(installDir string, args []string) error {
+	image, set := "", false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--image":
+			if i+1 >= len(args) || !validImageRef(args[i+1]) {
+				return fmt.Errorf("укажите образ: --image registry/имя:тег")
+			}
+			image, set = args[i+1], true
+			i++
+		case "--build":
+			image, set = "", true
+		}
+	}
+	if !set {
+		return nil
+	}
+	if _, ok := loadInstallConfig(installDir); !ok {
+		return fmt.Errorf("в .env нет секции INSTALLER — смена образа доступна после переустановки")
+	}
+	return updateEnvFile(filepath.Join(installDir, ".env"), map[string]string{"INSTALLER_BOT_IMAGE": image})
 }
 
 func uninstallBot() {
@@ -174,23 +266,26 @@ func uninstallBot() {
 	}
 
 	if ui.ConfirmPrompt("Создать резервную копию сначала?", true) {
-		runShellSilent(fmt.Sprintf(`cd %s && tar -czf "/root/bedolaga_backup_$(date +%%Y%%m%%d_%%H%%M%%S).tar.gz" .env data/ 2>/dev/null || true`, installDir))
+		runShellSilent(fmt.Sprintf(`cd %s && umask 077 && tar -czf "/root/bedolaga_backup_$(date +%%Y%%m%%d_%%H%%M%%S).tar.gz" .env data/ 2>/dev/null || true`, installDir))
 		ui.PrintSuccess("Резервная копия сохранена в /root/")
 	}
 
 	ui.RunWithSpinner("Остановка контейнеров...", func() error {
-		runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s down -v 2>/dev/null || docker compose down -v 2>/dev/null || true", installDir, composeFile))
+		runShellSilent(composeCmd(installDir, composeFile) + " down -v 2>/dev/null || docker compose down -v 2>/dev/null || true")
 		return nil
 	})
 
 	runShellSilent("rm -f /etc/nginx/sites-enabled/bedolaga-webhook /etc/nginx/sites-enabled/bedolaga-miniapp")
 	runShellSilent("rm -f /etc/nginx/sites-available/bedolaga-webhook /etc/nginx/sites-available/bedolaga-miniapp")
-	runShellSilent("nginx -t 2>/dev/null && systemctl reload nginx 2>/dev/null || true")
+	runShellSilent("rm -f /etc/nginx/conf.d/bedolaga-sites.conf /etc/nginx/http.d/bedolaga-sites.conf")
+	runShellSilent("nginx -t 2>/dev/null && " + serviceCmd("reload", "nginx") + " 2>/dev/null || true")
 	if fileExists("/etc/caddy/Caddyfile") {
 		runShellSilent(`sed -i '/# === BEGIN Bedolaga Bot ===/,/# === END Bedolaga Bot ===/d' /etc/caddy/Caddyfile`)
-		runShellSilent("systemctl reload caddy 2>/dev/null || true")
+		runShellSilent(serviceCmd("reload", "caddy") + " 2>/dev/null || true")
 	}
 	os.Remove("/usr/local/bin/bot")
+	os.Remove(logrotateConfig)
+	offerSwapRemoval()
 
 	if ui.ConfirmPrompt("Удалить каталог "+installDir+"?", false) {
 		os.RemoveAll(installDir)
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"text/template"
 )
 
 // ════════════════════════════════════════════════════════════════
@@ -11,29 +14,43 @@ import (
 // ════════════════════════════════════════════════════════════════
 
 func cloneRepository(cfg *Config) {
+	if cfg.Offline {
+		if err := cloneFromBundle(cfg); err != nil {
+			globalProgress.fail("Ошибка копирования из бандла: " + err.Error())
+			os.Exit(1)
+		}
+		globalProgress.done("Репозиторий скопирован из бандла")
+		return
+	}
+
 	if dirExists(cfg.InstallDir) {
-		// Обновляем существующий
-		runShellSilent(fmt.Sprintf("cd %s && git pull origin main 2>/dev/null || true", cfg.InstallDir))
+		// Обновляем существующий (compose-файлы будут сгенерированы заново)
+		// зеркало передаётся только в этот pull — origin остаётся GitHub
+		runShellSilent(fmt.Sprintf("cd %s && git checkout -- docker-compose.yml docker-compose.local.yml 2>/dev/null; git pull %s main 2>/dev/null || true", cfg.InstallDir, shellQuote(gitRepoURL(cfg))))
 		globalProgress.done("Репозиторий обновлён")
 		return
 	}
 
 	// Клонируем новый
-	_, err := runCmdSilent("git", "clone", repoURL, cfg.InstallDir)
+	_, err := runCmdSilent("git", "clone", gitRepoURL(cfg), cfg.InstallDir)
 	if err != nil {
 		globalProgress.fail("Ошибка клонирования: " + err.Error())
 		os.Exit(1)
 	}
+	if cfg.GitMirror != "" {
+		runCmdSilent("git", "-C", cfg.InstallDir, "remote", "set-url", "origin", repoURL)
+	}
 	globalProgress.done("Репозиторий клонирован")
 }
 
 func createDirectories(cfg *Config) {
-	dirs := []string{"logs", "data", "data/backups", "data/referral_qr", "locales"}
+	dirs := []string{"logs", "data", "data/referral_qr"}
 	for _, d := range dirs {
-		os.MkdirAll(filepath.Join(cfg.InstallDir, d), 0777)
+		os.MkdirAll(filepath.Join(cfg.InstallDir, d), botDirMode)
 	}
-	// Даём полные права чтобы Docker контейнер мог писать
-	runShellSilent(fmt.Sprintf("chmod -R 777 %s/logs %s/data 2>/dev/null || true", cfg.InstallDir, cfg.InstallDir))
+	os.MkdirAll(filepath.Join(cfg.InstallDir, "data", "backups"), secretDirMode)
+	os.MkdirAll(filepath.Join(cfg.InstallDir, "locales"), 0755)
+	// Владелец logs/ и data/ — пользователь контейнера, выставляется после запуска (ensureBotPermissions)
 	runShellSilent(fmt.Sprintf("chmod -R 755 %s/locales 2>/dev/null || true", cfg.InstallDir))
 }
 
@@ -41,194 +58,257 @@ func createDirectories(cfg *Config) {
 // DOCKER COMPOSE FILES
 // ════════════════════════════════════════════════════════════════
 
-func createStandaloneCompose(cfg *Config) {
-	content := `services:
+// composeResources — лимиты ресурсов одного сервиса (пусто = без лимита)
+type composeResources struct {
+	MemLimit string
+	CPUs     string
+}
+
+// composeOptions описывает вариант compose-файла поверх общей базы
+type composeOptions struct {
+	Core             bool   // postgres + redis + bot
+	Caddy            bool   // сервис Caddy (host network)
+	ExternalNetwork  string // внешняя Docker-сеть панели
+	Image            string // готовый образ бота вместо build: .
+	BindAddress      string // адрес публикации web API (пусто = все интерфейсы)
+	WebAPIPort       string // порт web API на хосте и в контейнере
+	MiniappDir       string // каталог miniapp для Caddy
+	RedisMaxMemory   string // --maxmemory Redis (по умолчанию 256mb)
+	PostgresCommand  string // команда postgres с -c параметрами (пусто = по умолчанию)
+	Timezone         string // TZ контейнера бота
+	NoHostTimezone   bool   // не монтировать /etc/timezone и /etc/localtime хоста
+	ExternalPostgres bool   // внешний PostgreSQL — без сервиса postgres
+	ExternalRedis    bool   // внешний Redis — без сервиса redis
+
+	Postgres composeResources
+	Redis    composeResources
+	Bot      composeResources
+}
+
+const composeTemplate = `{{define "resources"}}{{if .MemLimit}}
+    mem_limit: {{.MemLimit}}{{end}}{{if .CPUs}}
+    cpus: {{.CPUs}}{{end}}{{end}}
+{{- define "logging"}}
+    logging:
+      driver: json-file
+      options:
+        max-size: "10m"
+        max-file: "3"{{end}}
+{{- define "networks"}}
+    networks:
+      - bot_network{{if .ExternalNetwork}}
+      - remnawave_network{{end}}{{end}}
+{{- define "postgres"}}
   postgres:
     image: postgres:15-alpine
     container_name: remnawave_bot_db
-    restart: unless-stopped
+    restart: unless-stopped{{template "resources" .Postgres}}{{template "logging"}}{{if .PostgresCommand}}
+    command: {{.PostgresCommand}}{{end}}
     environment:
       POSTGRES_DB: ${POSTGRES_DB:-remnawave_bot}
       POSTGRES_USER: ${POSTGRES_USER:-remnawave_user}
       POSTGRES_PASSWORD: ${POSTGRES_PASSWORD:-secure_password_123}
       POSTGRES_INITDB_ARGS: "--encoding=UTF8 --locale=C"
     volumes:
-      - postgres_data:/var/lib/postgresql/data
-    networks:
-      - bot_network
+      - postgres_data:/var/lib/postgresql/data{{template "networks" .}}
     healthcheck:
       test: ["CMD-SHELL", "pg_isready -U ${POSTGRES_USER:-remnawave_user} -d ${POSTGRES_DB:-remnawave_bot}"]
       interval: 30s
       timeout: 5s
       retries: 5
       start_period: 30s
-
+{{end}}
+{{- define "redis"}}
   redis:
     image: redis:7-alpine
     container_name: remnawave_bot_redis
-    restart: unless-stopped
-    command: redis-server --appendonly yes --maxmemory 256mb --maxmemory-policy allkeys-lru
+    restart: unless-stopped{{template "resources" .Redis}}{{template "logging"}}
+    command: redis-server --appendonly yes --maxmemory {{or .RedisMaxMemory "256mb"}} --maxmemory-policy allkeys-lru
     volumes:
-      - redis_data:/data
-    networks:
-      - bot_network
+      - redis_data:/data{{template "networks" .}}
     healthcheck:
       test: ["CMD", "redis-cli", "ping"]
       interval: 30s
       timeout: 10s
       retries: 3
-
-  bot:
-    build: .
+{{end}}
+{{- define "bot"}}
+  bot:{{if .Image}}
+    image: {{.Image}}{{else}}
+    build: .{{end}}
     container_name: remnawave_bot
-    restart: unless-stopped
-    depends_on:
+    restart: unless-stopped{{template "resources" .Bot}}{{template "logging"}}
+{{- if not (and .ExternalPostgres .ExternalRedis)}}
+    depends_on:{{if not .ExternalPostgres}}
       postgres:
-        condition: service_healthy
+        condition: service_healthy{{end}}{{if not .ExternalRedis}}
       redis:
-        condition: service_healthy
+        condition: service_healthy{{end}}
+{{- end}}
     env_file:
       - .env
     environment:
       DOCKER_ENV: "true"
-      DATABASE_MODE: "auto"
+      DATABASE_MODE: "auto"{{if not .ExternalPostgres}}
       POSTGRES_HOST: "postgres"
       POSTGRES_PORT: "5432"
       POSTGRES_DB: "${POSTGRES_DB:-remnawave_bot}"
       POSTGRES_USER: "${POSTGRES_USER:-remnawave_user}"
-      POSTGRES_PASSWORD: "${POSTGRES_PASSWORD:-secure_password_123}"
-      REDIS_URL: "redis://redis:6379/0"
-      TZ: "Europe/Moscow"
+      POSTGRES_PASSWORD: "${POSTGRES_PASSWORD:-secure_password_123}"{{end}}{{if not .ExternalRedis}}
+      REDIS_URL: "redis://redis:6379/0"{{end}}
+      TZ: "{{or .Timezone "Europe/Moscow"}}"
       LOCALES_PATH: "${LOCALES_PATH:-/app/locales}"
     volumes:
       - ./logs:/app/logs:rw
       - ./data:/app/data:rw
-      - ./locales:/app/locales:rw
+      - ./locales:/app/locales:rw{{if not .NoHostTimezone}}
       - /etc/timezone:/etc/timezone:ro
-      - /etc/localtime:/etc/localtime:ro
+      - /etc/localtime:/etc/localtime:ro{{end}}
       - ./vpn_logo.png:/app/vpn_logo.png:ro
     ports:
-      - "${WEB_API_PORT:-8080}:8080"
-    networks:
-      - bot_network
+      - "{{if .BindAddress}}{{.BindAddress}}:{{end}}{{.WebAPIPort}}:{{.WebAPIPort}}"{{template "networks" .}}
     healthcheck:
-      test: ["CMD-SHELL", "wget -q --spider http://localhost:8080/health || exit 1"]
+      test: ["CMD-SHELL", "wget -q --spider http://localhost:{{.WebAPIPort}}/health || exit 1"]
       interval: 60s
       timeout: 10s
       retries: 3
       start_period: 30s
-
+{{- end}}
+{{- define "caddy"}}
+  caddy:
+    image: caddy:2-alpine
+    container_name: remnawave_caddy
+    restart: unless-stopped{{template "logging"}}
+    network_mode: host
+    volumes:
+      - ./caddy/Caddyfile:/etc/caddy/Caddyfile:ro
+      - caddy_data:/data
+      - caddy_config:/config{{if .MiniappDir}}
+      - {{.MiniappDir}}:/srv/miniapp:ro{{end}}
+{{- end -}}
+services:
+{{- if .Core}}
+{{- if not .ExternalPostgres}}{{template "postgres" .}}{{end}}
+{{- if not .ExternalRedis}}{{template "redis" .}}{{end}}
+{{- template "bot" .}}
+{{end}}
+{{- if .Caddy}}{{template "caddy" .}}
+{{end}}
+{{- $dbVolumes := and .Core (not (and .ExternalPostgres .ExternalRedis))}}
+{{- if or $dbVolumes .Caddy}}
 volumes:
+{{- if and .Core (not .ExternalPostgres)}}
   postgres_data:
     driver: local
+{{- end}}
+{{- if and .Core (not .ExternalRedis)}}
   redis_data:
     driver: local
-
-networks:
+{{- end}}
+{{- if .Caddy}}
+  caddy_data:
+    driver: local
+  caddy_config:
+    driver: local
+{{- end}}
+{{- end}}
+{{- if .Core}}
+{{if or $dbVolumes .Caddy}}
+{{end}}networks:
   bot_network:
     name: remnawave_bot_network
     driver: bridge
+{{- if .ExternalNetwork}}
+  remnawave_network:
+    name: {{.ExternalNetwork}}
+    external: true
+{{- end}}
+{{- end}}
 `
-	os.WriteFile(filepath.Join(cfg.InstallDir, "docker-compose.yml"), []byte(content), 0644)
+
+var composeTmpl = template.Must(template.New("compose").Parse(composeTemplate))
+
+func renderCompose(opts composeOptions) (string, error) {
+	var buf bytes.Buffer
+	if err := composeTmpl.Execute(&buf, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
-func createLocalCompose(cfg *Config) {
-	networkName := cfg.DockerNetwork
-	if networkName == "" {
-		networkName = "remnawave-network"
+func writeCompose(cfg *Config, name string, opts composeOptions) {
+	content, err := renderCompose(opts)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(cfg.InstallDir, name), []byte(content), 0644)
 	}
-	content := fmt.Sprintf(`services:
-  postgres:
-    image: postgres:15-alpine
-    container_name: remnawave_bot_db
-    restart: unless-stopped
-    environment:
-      POSTGRES_DB: ${POSTGRES_DB:-remnawave_bot}
-      POSTGRES_USER: ${POSTGRES_USER:-remnawave_user}
-      POSTGRES_PASSWORD: ${POSTGRES_PASSWORD:-secure_password_123}
-      POSTGRES_INITDB_ARGS: "--encoding=UTF8 --locale=C"
-    volumes:
-      - postgres_data:/var/lib/postgresql/data
-    networks:
-      - bot_network
-      - remnawave_network
-    healthcheck:
-      test: ["CMD-SHELL", "pg_isready -U ${POSTGRES_USER:-remnawave_user} -d ${POSTGRES_DB:-remnawave_bot}"]
-      interval: 30s
-      timeout: 5s
-      retries: 5
-      start_period: 30s
+	if err != nil {
+		globalProgress.fail("Ошибка записи " + name + ": " + err.Error())
+		os.Exit(1)
+	}
+}
 
-  redis:
-    image: redis:7-alpine
-    container_name: remnawave_bot_redis
-    restart: unless-stopped
-    command: redis-server --appendonly yes --maxmemory 256mb --maxmemory-policy allkeys-lru
-    volumes:
-      - redis_data:/data
-    networks:
-      - bot_network
-      - remnawave_network
-    healthcheck:
-      test: ["CMD", "redis-cli", "ping"]
-      interval: 30s
-      timeout: 10s
-      retries: 3
+// botComposeOptions — базовые опции compose для бота из конфигурации установки
+func botComposeOptions(cfg *Config) composeOptions {
+	opts := composeOptions{
+		Core:        true,
+		Image:       cfg.BotImage,
+		BindAddress: composeBindAddress(cfg.WebAPIBind),
+		WebAPIPort:  webAPIPort(cfg),
+		Timezone:    timezoneOrDefault(cfg),
 
-  bot:
-    build: .
-    container_name: remnawave_bot
-    restart: unless-stopped
-    depends_on:
-      postgres:
-        condition: service_healthy
-      redis:
-        condition: service_healthy
-    env_file:
-      - .env
-    environment:
-      DOCKER_ENV: "true"
-      DATABASE_MODE: "auto"
-      POSTGRES_HOST: "postgres"
-      POSTGRES_PORT: "5432"
-      POSTGRES_DB: "${POSTGRES_DB:-remnawave_bot}"
-      POSTGRES_USER: "${POSTGRES_USER:-remnawave_user}"
-      POSTGRES_PASSWORD: "${POSTGRES_PASSWORD:-secure_password_123}"
-      REDIS_URL: "redis://redis:6379/0"
-      TZ: "Europe/Moscow"
-      LOCALES_PATH: "${LOCALES_PATH:-/app/locales}"
-    volumes:
-      - ./logs:/app/logs:rw
-      - ./data:/app/data:rw
-      - ./locales:/app/locales:rw
-      - /etc/timezone:/etc/timezone:ro
-      - /etc/localtime:/etc/localtime:ro
-      - ./vpn_logo.png:/app/vpn_logo.png:ro
-    ports:
-      - "${WEB_API_PORT:-8080}:8080"
-    networks:
-      - bot_network
-      - remnawave_network
-    healthcheck:
-      test: ["CMD-SHELL", "wget -q --spider http://localhost:8080/health || exit 1"]
-      interval: 60s
-      timeout: 10s
-      retries: 3
-      start_period: 30s
+		ExternalPostgres: externalPostgres(cfg),
+		ExternalRedis:    externalRedis(cfg),
+	}
+	// файлы хоста перебили бы TZ, если пояс отличается от серверного
+	if cfg.Timezone != "" && cfg.Timezone != detectHostTimezone() {
+		opts.NoHostTimezone = true
+	}
+	if p, ok := findResourceProfile(cfg.ResourceProfile); ok {
+		applyResourceProfile(&opts, p, runtime.NumCPU())
+	}
+	return opts
+}
 
-volumes:
-  postgres_data:
-    driver: local
-  redis_data:
-    driver: local
+func createStandaloneCompose(cfg *Config) {
+	writeCompose(cfg, "docker-compose.yml", botComposeOptions(cfg))
+}
 
-networks:
-  bot_network:
-    name: remnawave_bot_network
-    driver: bridge
-  remnawave_network:
-    name: %s
-    external: true
-`, networkName)
-	os.WriteFile(filepath.Join(cfg.InstallDir, "docker-compose.local.yml"), []byte(content), 0644)
+func createLocalCompose(cfg *Config) {
+	opts := botComposeOptions(cfg)
+	opts.ExternalNetwork = cfg.DockerNetwork
+	if opts.ExternalNetwork == "" {
+		opts.ExternalNetwork = "remnawave-network"
+	}
+	writeCompose(cfg, "docker-compose.local.yml", opts)
+}
+
+// writeBotCompose генерирует compose-файл стека бота и возвращает его имя
+func writeBotCompose(cfg *Config) string {
+	if cfg.PanelInstalledLocally {
+		createLocalCompose(cfg)
+		return "docker-compose.local.yml"
+	}
+	createStandaloneCompose(cfg)
+	return "docker-compose.yml"
+}
+
+// pullBotRepo обновляет код бота. Сгенерированные compose-файлы откатываются
+// перед git pull (иначе pull упадёт на локальных изменениях) и создаются
+// заново из настроек установщика в .env.
+func pullBotRepo(installDir string) error {
+	cfg, ok := loadInstallConfig(installDir)
+	if cfg.Offline {
+		return fmt.Errorf("офлайн-установка: обновите бота новым бандлом (install --offline)")
+	}
+	applyNetworkEnv(cfg)
+	if ok {
+		for _, f := range []string{"docker-compose.yml", "docker-compose.local.yml"} {
+			runShellSilent(fmt.Sprintf("cd %s && git checkout -- %s 2>/dev/null || true", installDir, f))
+		}
+	}
+	_, err := runShellSilent(fmt.Sprintf("cd %s && git pull %s main", installDir, shellQuote(gitRepoURL(cfg))))
+	if ok {
+		writeBotCompose(cfg)
+	}
+	return err
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "перезаписать golden-файлы в testdata/")
+
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v (запустите go test -update)", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from golden:\n%s", name, got)
+	}
+}
+
+func TestRenderComposeGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		opts   composeOptions
+	}{
+		{"standalone.yml", composeOptions{Core: true, WebAPIPort: "8080"}},
+		{"local.yml", composeOptions{Core: true, WebAPIPort: "8080", ExternalNetwork: "remnawave-network"}},
+		{"external_db.yml", composeOptions{Core: true, WebAPIPort: "8080", ExternalPostgres: true, ExternalRedis: true}},
+		{"external_postgres.yml", composeOptions{Core: true, WebAPIPort: "8080", ExternalPostgres: true}},
+		{"caddy.yml", composeOptions{Caddy: true}},
+		{"caddy_miniapp.yml", composeOptions{Caddy: true, MiniappDir: "/opt/bot/miniapp"}},
+		{"full.yml", composeOptions{
+			Core:            true,
+			Caddy:           true,
+			ExternalNetwork: "remnawave-network",
+			Image:           "ghcr.io/example/bot:1.0.0",
+			BindAddress:     "127.0.0.1",
+			WebAPIPort:      "9090",
+			Timezone:        "Asia/Tokyo",
+			NoHostTimezone:  true,
+			MiniappDir:      "/opt/bot/miniapp",
+			Postgres:        composeResources{MemLimit: "512m", CPUs: "1.0"},
+			Redis:           composeResources{MemLimit: "256m"},
+			Bot:             composeResources{CPUs: "0.5"},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := renderCompose(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.golden, err)
+		}
+		checkGolden(t, filepath.Join("compose", tt.golden), got)
+	}
+}
+
+func TestBotComposeOptionsPort(t *testing.T) {
+	opts := botComposeOptions(&Config{WebAPIPort: "9091", WebAPIBind: "127.0.0.1"})
+	got, err := renderCompose(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"127.0.0.1:9091:9091"`, "http://localhost:9091/health"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("compose missing %s", want)
+		}
+	}
+}
+
+func TestRenderComposeVariantsShareBase(t *testing.T) {
+	standalone, _ := renderCompose(botComposeOptions(&Config{}))
+	local, _ := renderCompose(composeOptions{Core: true, WebAPIPort: "8080", ExternalNetwork: "panel-net"})
+
+	stripped := strings.ReplaceAll(local, "\n      - remnawave_network", "")
+	stripped = strings.TrimSuffix(stripped, "  remnawave_network:\n    name: panel-net\n    external: true\n")
+	if stripped != standalone {
+		t.Error("local compose should differ from standalone only by the external network")
+	}
+}
+
+func TestApplyResourceProfile(t *testing.T) {
+	small, _ := findResourceProfile("small")
+	opts := composeOptions{Core: true, WebAPIPort: "8080"}
+	applyResourceProfile(&opts, small, 1)
+	if opts.Bot.CPUs != "1.0" || opts.Postgres.CPUs != "0.5" {
+		t.Errorf("cpus: bot %s postgres %s", opts.Bot.CPUs, opts.Postgres.CPUs)
+	}
+	got, _ := renderCompose(opts)
+	checkGolden(t, filepath.Join("compose", "small.yml"), got)
+
+	large, _ := findResourceProfile("large")
+	applyResourceProfile(&opts, large, 1)
+	if opts.Bot.CPUs != "1.0" {
+		t.Errorf("cpus should be clamped to host CPUs, got %s", opts.Bot.CPUs)
+	}
+}
+
+func TestRecommendResourceProfile(t *testing.T) {
+	tests := map[int]string{960: "small", 1536: "small", 2048: "medium", 3900: "medium", 7900: "large", 64000: "large"}
+	for mem, want := range tests {
+		if got := recommendResourceProfile(mem).Name; got != want {
+			t.Errorf("recommendResourceProfile(%d) = %s, want %s", mem, got, want)
+		}
+	}
+	if mb := parseMemTotalMB("MemTotal:        2030428 kB\nMemFree: 1 kB\n"); mb != 1982 {
+		t.Errorf("parseMemTotalMB = %d", mb)
+	}
+}
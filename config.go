@@ -30,6 +30,13 @@ type Config struct {
 	KeepExistingVolumes bool
 	OldPostgresPassword string
 
+	PostgresHost    string
+	PostgresPort    string
+	PostgresDB      string
+	PostgresUser    string
+	PostgresSSLMode string
+	RedisURL        string
+
 	WebhookSecretToken string
 	WebAPIDefaultToken string
 	BotRunMode         string
@@ -38,4 +45,25 @@ type Config struct {
 
 	ReverseProxyType string
 	SSLEmail         string
+	WebAPIBind       string
+	WebAPIPort       string
+	ResourceProfile  string
+	BotImage         string
+	Offline          bool
+	OfflineBundleDir string
+
+	HTTPProxy         string
+	NoProxy           string
+	RegistryMirror    string
+	DockerDownloadURL string
+	GitMirror         string
+	AptMirror         string
+
+	Timezone           string
+	DefaultLanguage    string
+	AvailableLanguages string
+
+	LogMaxSize string
+	LogKeep    string
+	LogMaxAge  string
 }
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// DATABASE (built-in or external PostgreSQL / Redis)
+// ════════════════════════════════════════════════════════════════
+
+const (
+	defaultPostgresHost = "postgres"
+	defaultPostgresPort = "5432"
+	defaultPostgresDB   = "remnawave_bot"
+	defaultPostgresUser = "remnawave_user"
+	defaultRedisURL     = "redis://redis:6379/0"
+)
+
+func postgresHost(cfg *Config) string { return orDefault(cfg.PostgresHost, defaultPostgresHost) }
+func postgresPort(cfg *Config) string { return orDefault(cfg.PostgresPort, defaultPostgresPort) }
+func postgresDB(cfg *Config) string   { return orDefault(cfg.PostgresDB, defaultPostgresDB) }
+func postgresUser(cfg *Config) string { return orDefault(cfg.PostgresUser, defaultPostgresUser) }
+func redisURL(cfg *Config) string     { return orDefault(cfg.RedisURL, defaultRedisURL) }
+
+func externalPostgres(cfg *Config) bool { return postgresHost(cfg) != defaultPostgresHost }
+func externalRedis(cfg *Config) bool    { return redisURL(cfg) != defaultRedisURL }
+
+// pgClientCmd — команда клиента PostgreSQL (psql, pg_dump, pg_isready) для базы бота
+// и окружение только для неё. Встроенная БД — через docker compose exec, внешняя —
+// одноразовым контейнером в сети хоста. Пароль передаётся через окружение, а не в командной строке.
+func pgClientCmd(cfg *Config, composeFile, tool string) (string, []string) {
+	if !externalPostgres(cfg) {
+		return fmt.Sprintf("%s exec -T postgres %s -U %s", composeCmd(cfg.InstallDir, composeFile), tool, shellQuote(postgresUser(cfg))), nil
+	}
+	env := []string{
+		"PGPASSWORD=" + cfg.PostgresPassword,
+		"PGSSLMODE=" + orDefault(cfg.PostgresSSLMode, "prefer"),
+	}
+	return fmt.Sprintf("docker run --rm -i --network host -e PGPASSWORD -e PGSSLMODE postgres:15-alpine %s -h %s -p %s -U %s",
+		tool, shellQuote(postgresHost(cfg)), shellQuote(postgresPort(cfg)), shellQuote(postgresUser(cfg))), env
+}
+
+// redisCLIArgs разбирает REDIS_URL в аргументы redis-cli; пароль возвращается
+// отдельно для REDISCLI_AUTH, чтобы не попасть в список процессов
+func redisCLIArgs(rawURL string) (args, password string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" || (u.Scheme != "redis" && u.Scheme != "rediss") {
+		return "", "", fmt.Errorf("неверный REDIS_URL")
+	}
+	parts := []string{"-h", u.Hostname(), "-p", orDefault(u.Port(), "6379")}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		parts = append(parts, "-n", db)
+	}
+	if u.User != nil {
+		password, _ = u.User.Password()
+		if name := u.User.Username(); name != "" {
+			parts = append(parts, "--user", name)
+		}
+	}
+	if u.Scheme == "rediss" {
+		parts = append(parts, "--tls")
+	}
+	for i, p := range parts {
+		parts[i] = shellQuote(p)
+	}
+	return strings.Join(parts, " "), password, nil
+}
+
+// redisPingCmd — redis-cli ping для Redis бота и окружение только для этой команды
+func redisPingCmd(cfg *Config, composeFile string) (string, []string, error) {
+	if !externalRedis(cfg) {
+		return composeCmd(cfg.InstallDir, composeFile) + " exec -T redis redis-cli ping", nil, nil
+	}
+	args, password, err := redisCLIArgs(redisURL(cfg))
+	if err != nil {
+		return "", nil, err
+	}
+	return "docker run --rm --network host -e REDISCLI_AUTH redis:7-alpine redis-cli " + args + " ping",
+		[]string{"REDISCLI_AUTH=" + password}, nil
+}
+
+func testPostgresConnection(cfg *Config) error {
+	cmd, env := pgClientCmd(cfg, "", "psql")
+	out, err := runShellSilentEnv(cmd+fmt.Sprintf(" -d %s -tAc 'SELECT 1' 2>&1", shellQuote(postgresDB(cfg))), env...)
+	if err != nil || strings.TrimSpace(out) != "1" {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	return nil
+}
+
+func testRedisConnection(cfg *Config) error {
+	cmd, env, err := redisPingCmd(cfg, "")
+	if err != nil {
+		return err
+	}
+	out, err := runShellSilentEnv(cmd+" 2>&1", env...)
+	if err != nil || !strings.Contains(out, "PONG") {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	return nil
+}
+
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return lines[len(lines)-1]
+}
+
+// selectDatabaseMode — встроенные контейнеры или внешние PostgreSQL/Redis
+func selectDatabaseMode(cfg *Config) {
+	idx := ui.SelectOption("База данных", []ui.SelectItem{
+		{Title: "Встроенные PostgreSQL и Redis", Description: "Контейнеры рядом с ботом (по умолчанию)"},
+		{Title: "Внешний PostgreSQL", Description: "Управляемая или общая БД, Redis встроенный"},
+		{Title: "Внешний Redis", Description: "PostgreSQL встроенный, Redis внешний"},
+		{Title: "Внешние PostgreSQL и Redis", Description: "Бот без контейнеров БД"},
+	})
+	if idx == 1 || idx == 3 {
+		inputExternalPostgres(cfg)
+	}
+	if idx == 2 || idx == 3 {
+		inputExternalRedis(cfg)
+	}
+}
+
+func inputExternalPostgres(cfg *Config) {
+	for {
+		cfg.PostgresHost = ui.InputText("POSTGRES_HOST", "db.example.com", "Адрес внешнего PostgreSQL", true)
+		cfg.PostgresPort = orDefault(ui.InputText("POSTGRES_PORT (необязательно)", defaultPostgresPort, "", false), defaultPostgresPort)
+		cfg.PostgresDB = orDefault(ui.InputText("POSTGRES_DB (необязательно)", defaultPostgresDB, "", false), defaultPostgresDB)
+		cfg.PostgresUser = orDefault(ui.InputText("POSTGRES_USER (необязательно)", defaultPostgresUser, "", false), defaultPostgresUser)
+		cfg.PostgresPassword = ui.InputSecret("POSTGRES_PASSWORD", "", "Пароль пользователя внешней БД", true)
+
+		sslModes := []ui.SelectItem{
+			{Title: "prefer", Description: "TLS, если сервер поддерживает"},
+			{Title: "require", Description: "Только TLS"},
+			{Title: "disable", Description: "Без TLS (локальная сеть)"},
+			{Title: "verify-full", Description: "TLS с проверкой сертификата и имени"},
+		}
+		cfg.PostgresSSLMode = sslModes[ui.SelectOption("sslmode", sslModes)].Title
+
+		var err error
+		ui.RunWithSpinner("Проверка подключения к PostgreSQL...", func() error {
+			err = testPostgresConnection(cfg)
+			return err
+		})
+		if err == nil {
+			ui.PrintSuccess("PostgreSQL: подключение успешно")
+			return
+		}
+		ui.PrintError("PostgreSQL: " + err.Error())
+		if !retryConnectionPrompt() {
+			return
+		}
+	}
+}
+
+func inputExternalRedis(cfg *Config) {
+	for {
+		cfg.RedisURL = ui.InputSecret("REDIS_URL", "redis://:password@redis.example.com:6379/0", "URL внешнего Redis (rediss:// для TLS)", true)
+
+		var err error
+		ui.RunWithSpinner("Проверка подключения к Redis...", func() error {
+			err = testRedisConnection(cfg)
+			return err
+		})
+		if err == nil {
+			ui.PrintSuccess("Redis: подключение успешно")
+			return
+		}
+		ui.PrintError("Redis: " + err.Error())
+		if !retryConnectionPrompt() {
+			return
+		}
+	}
+}
+
+// retryConnectionPrompt: true — ввести данные заново, false — продолжить как есть
+func retryConnectionPrompt() bool {
+	if !ui.IsInteractive() {
+		return false
+	}
+	idx := ui.SelectOption("Что делать?", []ui.SelectItem{
+		{Title: "Ввести заново", Description: "Исправить параметры подключения"},
+		{Title: "Продолжить без проверки", Description: "Сервер может быть недоступен только с этого хоста"},
+	})
+	return idx == 0
+}
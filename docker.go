@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -18,27 +19,25 @@ func startDocker(cfg *Config) {
 	runShellSilent(fmt.Sprintf("cd %s && docker compose -f docker-compose.local.yml down 2>/dev/null || true", cfg.InstallDir))
 	runShellSilent(fmt.Sprintf("cd %s && docker compose -f docker-compose.caddy.yml down 2>/dev/null || true", cfg.InstallDir))
 
-	composeFile := "docker-compose.yml"
-
-	if cfg.PanelInstalledLocally {
-		if cfg.DockerNetwork != "" {
-			runShellSilent(fmt.Sprintf("docker network create %s 2>/dev/null || true", cfg.DockerNetwork))
-		}
-		createLocalCompose(cfg)
-		composeFile = "docker-compose.local.yml"
-	} else {
-		if !fileExists(filepath.Join(cfg.InstallDir, "docker-compose.yml")) {
-			createStandaloneCompose(cfg)
-		}
+	if cfg.PanelInstalledLocally && cfg.DockerNetwork != "" {
+		runShellSilent(fmt.Sprintf("docker network create %s 2>/dev/null || true", cfg.DockerNetwork))
 	}
+	// compose генерируется всегда, чтобы учитывать адрес публикации web API
+	composeFile := writeBotCompose(cfg)
 
 	// Создаём сеть бота заранее (нужна для Caddy)
 	runShellSilent("docker network create remnawave_bot_network 2>/dev/null || true")
 
+	var fellBack bool
 	ui.RunWithSpinner("Сборка и запуск контейнеров...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s up -d --build 2>&1", cfg.InstallDir, composeFile))
+		var err error
+		fellBack, err = upBotStack(cfg, composeFile)
 		return err
 	})
+	if fellBack {
+		ui.PrintWarning("Образ " + cfg.BotImage + " недоступен — бот собран из исходников")
+	}
+	ensureBotPermissions(cfg.InstallDir, composeFile)
 
 	// Если выбран Caddy — запускаем его контейнер
 	if cfg.ReverseProxyType == "caddy" {
@@ -55,7 +54,7 @@ func startDocker(cfg *Config) {
 	time.Sleep(8 * time.Second)
 
 	// Показываем статус основного compose
-	out, _ := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s ps --format 'table {{.Name}}\\t{{.Status}}' 2>/dev/null", cfg.InstallDir, composeFile))
+	out, _ := runShellSilent(composeCmd(cfg.InstallDir, composeFile) + " ps --format 'table {{.Name}}\\t{{.Status}}' 2>/dev/null")
 	if out != "" {
 		fmt.Println()
 		fmt.Println(ui.DimStyle.Render("  " + strings.ReplaceAll(out, "\n", "\n  ")))
@@ -73,7 +72,67 @@ func startDocker(cfg *Config) {
 
 	if cfg.PanelInstalledLocally && cfg.DockerNetwork != "" {
 		ensureNetworkConnection(cfg)
-		verifyPanelConnection()
+		verifyPanelConnection(cfg)
+	}
+}
+
+// upBotStack поднимает стек бота: готовый образ скачивается (docker compose pull),
+// иначе образ собирается локально. Если образ недоступен — откат на сборку,
+// настройка INSTALLER_BOT_IMAGE при этом сохраняется для следующего обновления.
+func upBotStack(cfg *Config, composeFile string) (fellBack bool, err error) {
+	dc := composeCmd(cfg.InstallDir, composeFile)
+	if cfg.BotImage == "" {
+		_, err = runShellSilent(dc + " up -d --build 2>&1")
+		return false, err
+	}
+	// офлайн-установка: образ уже загружен из бандла (docker load)
+	if cfg.Offline {
+		_, err = runShellSilent(dc + " up -d 2>&1")
+		return false, err
+	}
+	if _, err = runShellSilent(dc + " pull bot 2>&1"); err == nil {
+		_, err = runShellSilent(dc + " up -d 2>&1")
+		return false, err
+	}
+	buildCfg := *cfg
+	buildCfg.BotImage = ""
+	composeFile = writeBotCompose(&buildCfg)
+	_, err = runShellSilent(composeCmd(cfg.InstallDir, composeFile) + " up -d --build 2>&1")
+	return true, err
+}
+
+var imageRefRe = regexp.MustCompile(`^[a-z0-9]+([._/:-][a-z0-9]+)*(:[A-Za-z0-9_][A-Za-z0-9._-]{0,127})?(@sha256:[a-f0-9]{64})?$`)
+
+// validImageRef проверяет ссылку на образ: registry/name:tag или name@sha256:digest
+func validImageRef(ref string) bool {
+	return imageRefRe.MatchString(ref)
+}
+
+// selectBotImage — сборка бота из исходников или готовый образ из registry
+func selectBotImage(cfg *Config) {
+	if cfg.Offline {
+		ui.PrintInfo("Образ бота из офлайн-бандла: " + cfg.BotImage)
+		return
+	}
+	idx := ui.SelectOption("Образ бота", []ui.SelectItem{
+		{Title: "Сборка из исходников", Description: "docker compose build — долго на 1 CPU, нужен доступ к PyPI"},
+		{Title: "Готовый образ", Description: "Скачать образ из registry (тег или digest)"},
+	})
+	if idx == 0 {
+		cfg.BotImage = ""
+		return
+	}
+	for {
+		ref := ui.InputText("Образ бота", "ghcr.io/bedolaga-dev/remnawave-bedolaga-telegram-bot:latest", "registry/имя:тег или registry/имя@sha256:...", true)
+		if validImageRef(ref) {
+			cfg.BotImage = ref
+			return
+		}
+		ui.PrintError("Неверная ссылка на образ: " + ref)
+		if !ui.IsInteractive() {
+			cfg.BotImage = ""
+			return
+		}
 	}
 }
 
@@ -92,13 +151,14 @@ func ensureNetworkConnection(cfg *Config) {
 	}
 }
 
-func verifyPanelConnection() {
+func verifyPanelConnection(cfg *Config) {
 	time.Sleep(3 * time.Second)
 	if out, err := runShellSilent("docker exec remnawave_bot getent hosts remnawave 2>/dev/null | awk '{print $1}'"); err == nil && out != "" {
-		ui.PrintSuccess("Подключение к панели проверено: remnawave -> " + out + ":3000")
+		ui.PrintSuccess("Сеть панели: remnawave -> " + out)
 	} else {
 		ui.PrintWarning("Не удаётся разрешить 'remnawave' — проверьте сетевое подключение вручную")
 	}
+	checkPanelAPI(cfg)
 }
 
 // ════════════════════════════════════════════════════════════════
@@ -106,12 +166,29 @@ func verifyPanelConnection() {
 // ════════════════════════════════════════════════════════════════
 
 func setupFirewall() {
-	if !ui.ConfirmPrompt("Настроить Firewall (UFW)?", false) {
+	pm := pkgManager()
+	name := "UFW"
+	if pm.rhelFamily() {
+		name = "firewalld"
+	}
+	if !ui.ConfirmPrompt("Настроить Firewall ("+name+")?", false) {
 		return
 	}
 	ui.RunWithSpinner("Настройка firewall...", func() error {
+		if pm.rhelFamily() {
+			if !commandExists("firewall-cmd") {
+				pm.Install("firewalld")
+			}
+			runShellSilent(serviceCmd("enable", "firewalld"))
+			runShellSilent(serviceCmd("start", "firewalld"))
+			for _, svc := range []string{"ssh", "http", "https"} {
+				runShellSilent("firewall-cmd --permanent --add-service=" + svc)
+			}
+			runShellSilent("firewall-cmd --reload")
+			return nil
+		}
 		if !commandExists("ufw") {
-			runShellSilent("apt-get install -y ufw")
+			pm.Install("ufw")
 		}
 		runShellSilent("ufw --force reset")
 		runShellSilent("ufw default deny incoming")
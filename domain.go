@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: DOMAINS
+// ════════════════════════════════════════════════════════════════
+
+const domainUsage = "Использование: bot domain | bot domain set webhook|miniapp <домен> | bot domain remove webhook|miniapp"
+
+func miniappURL(domain string) string {
+	return "https://" + domain
+}
+
+// parseDomainArgs разбирает: set webhook|miniapp <домен> | remove webhook|miniapp
+func parseDomainArgs(args []string) (action, kind, domain string, err error) {
+	if len(args) < 2 {
+		return "", "", "", fmt.Errorf("не указано действие или тип домена")
+	}
+	action, kind = args[0], args[1]
+	if kind != "webhook" && kind != "miniapp" {
+		return "", "", "", fmt.Errorf("неизвестный тип домена: %s", kind)
+	}
+	switch action {
+	case "set":
+		if len(args) > 2 {
+			domain = args[2]
+		}
+	case "remove", "delete":
+		action = "remove"
+	default:
+		return "", "", "", fmt.Errorf("неизвестное действие: %s", action)
+	}
+	if len(args) > 3 || (action == "remove" && len(args) > 2) {
+		return "", "", "", fmt.Errorf("лишние аргументы")
+	}
+	return action, kind, strings.ToLower(cleanDomain(domain)), nil
+}
+
+func manageDomain(installDir, composeFile string, args []string) {
+	cfg, _ := loadInstallConfig(installDir)
+	if cfg.MiniappDomain == "" {
+		cfg.MiniappDomain = miniappDomainFromProxy(cfg)
+	}
+	if len(args) == 0 {
+		printDomains(cfg)
+		return
+	}
+	action, kind, domain, err := parseDomainArgs(args)
+	if err != nil {
+		ui.PrintError(err.Error())
+		ui.PrintDim(domainUsage)
+		return
+	}
+	if action == "set" && domain == "" && !ui.IsInteractive() {
+		ui.PrintError("Укажите домен: " + domainUsage)
+		return
+	}
+
+	fmt.Println()
+	switch {
+	case kind == "webhook" && action == "set":
+		switchToWebhook(cfg, composeFile, domain)
+	case kind == "webhook":
+		if cfg.WebhookDomain == "" {
+			ui.PrintInfo("Домен вебхука не настроен")
+			return
+		}
+		switchToPolling(cfg, composeFile, readEnvFile(filepath.Join(installDir, ".env"))["BOT_TOKEN"])
+	case action == "set":
+		if domain = resolveDomain(domain, "Домен Mini App"); domain != "" {
+			setMiniappDomain(cfg, composeFile, domain)
+		}
+	default:
+		if cfg.MiniappDomain == "" {
+			ui.PrintInfo("Домен Mini App не настроен")
+			return
+		}
+		setMiniappDomain(cfg, composeFile, "")
+	}
+}
+
+func printDomains(cfg *Config) {
+	fmt.Println()
+	fmt.Println(ui.AccentBar.Render("  ДОМЕНЫ"))
+	fmt.Println()
+	ui.PrintInfo("Вебхук:   " + orDefault(cfg.WebhookDomain, "не настроен") + " (режим " + orDefault(cfg.BotRunMode, "polling") + ")")
+	ui.PrintInfo("Mini App: " + orDefault(cfg.MiniappDomain, "не настроен"))
+	ui.PrintInfo("Прокси:   " + orDefault(cfg.ReverseProxyType, "skip"))
+	fmt.Println()
+	ui.PrintDim(domainUsage)
+}
+
+// setMiniappDomain назначает (domain != "") или убирает домен Mini App:
+// сайт в прокси, сертификат, MINIAPP_CUSTOM_URL и перезапуск бота
+func setMiniappDomain(cfg *Config, composeFile, domain string) {
+	envPath := filepath.Join(cfg.InstallDir, ".env")
+	old := cfg.MiniappDomain
+	cfg.MiniappDomain = domain
+
+	proxy := cfg.ReverseProxyType != "" && cfg.ReverseProxyType != "skip"
+	if domain != "" {
+		proxy = ensureReverseProxy(cfg)
+	}
+	if proxy {
+		// без сертификата nginx панели сослался бы на несуществующий /etc/letsencrypt/live
+		if err := updateReverseProxy(cfg, domain); err != nil {
+			ui.PrintError("Прокси не обновлён: " + err.Error())
+			ui.PrintDim(".env не изменён, домен Mini App прежний")
+			return
+		}
+		offerCertificateRemoval(cfg, old)
+	}
+
+	// web API нужен Mini App и вебхуку
+	webAPI := "false"
+	if domain != "" || cfg.BotRunMode == "webhook" {
+		webAPI = "true"
+	}
+	customURL := ""
+	if domain != "" {
+		customURL = miniappURL(domain)
+	}
+	updates := map[string]string{
+		"MINIAPP_CUSTOM_URL":       customURL,
+		"WEB_API_ENABLED":          webAPI,
+		"INSTALLER_MINIAPP_DOMAIN": domain,
+		"INSTALLER_REVERSE_PROXY":  cfg.ReverseProxyType,
+	}
+	if err := updateEnvFile(envPath, updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	if domain != "" {
+		ui.PrintSuccess(".env обновлён: MINIAPP_CUSTOM_URL=" + customURL)
+	} else {
+		ui.PrintSuccess("Домен Mini App " + old + " удалён")
+	}
+
+	if err := recreateBot(cfg.InstallDir, composeFile); err != nil {
+		ui.PrintError("Ошибка перезапуска: " + err.Error())
+		return
+	}
+	ui.PrintSuccess("Бот перезапущен")
+}
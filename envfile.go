@@ -2,9 +2,14 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"bedolaga-installer/pkg/ui"
 )
 
 // ════════════════════════════════════════════════════════════════
@@ -21,7 +26,7 @@ func createEnvFile(cfg *Config) {
 		adminNotifChatID = ""
 	}
 
-	if cfg.KeepExistingVolumes && cfg.OldPostgresPassword != "" {
+	if cfg.KeepExistingVolumes && cfg.OldPostgresPassword != "" && !externalPostgres(cfg) {
 		cfg.PostgresPassword = cfg.OldPostgresPassword
 	}
 
@@ -50,6 +55,31 @@ func createEnvFile(cfg *Config) {
 
 	cabinetJWTSecret := generateToken()
 
+	miniappURLLine := "#MINIAPP_CUSTOM_URL="
+	if cfg.MiniappDomain != "" {
+		miniappURLLine = "MINIAPP_CUSTOM_URL=" + miniappURL(cfg.MiniappDomain)
+	}
+
+	installerLines := strings.Join([]string{
+		envLine("INSTALLER_REVERSE_PROXY", cfg.ReverseProxyType),
+		envLine("INSTALLER_MINIAPP_DOMAIN", cfg.MiniappDomain),
+		envLine("INSTALLER_PANEL_DIR", cfg.PanelDir),
+		envLine("INSTALLER_DOCKER_NETWORK", cfg.DockerNetwork),
+		envLine("INSTALLER_WEB_API_BIND", cfg.WebAPIBind),
+		envLine("INSTALLER_RESOURCE_PROFILE", cfg.ResourceProfile),
+		envLine("INSTALLER_BOT_IMAGE", cfg.BotImage),
+		envLine("INSTALLER_OFFLINE", boolEnv(cfg.Offline)),
+		envLine("INSTALLER_HTTP_PROXY", cfg.HTTPProxy),
+		envLine("INSTALLER_NO_PROXY", cfg.NoProxy),
+		envLine("INSTALLER_REGISTRY_MIRROR", cfg.RegistryMirror),
+		envLine("INSTALLER_DOCKER_REPO", cfg.DockerDownloadURL),
+		envLine("INSTALLER_GIT_MIRROR", cfg.GitMirror),
+		envLine("INSTALLER_APT_MIRROR", cfg.AptMirror),
+		envLine("INSTALLER_LOG_MAX_SIZE", cfg.LogMaxSize),
+		envLine("INSTALLER_LOG_KEEP", cfg.LogKeep),
+		envLine("INSTALLER_LOG_MAX_AGE", cfg.LogMaxAge),
+	}, "\n")
+
 	env := fmt.Sprintf(`# ===============================================
 # REMNAWAVE BEDOLAGA BOT CONFIGURATION
 # ===============================================
@@ -64,12 +94,13 @@ SUPPORT_USERNAME=%s
 
 # ===== DATABASE (ОБЯЗАТЕЛЬНО) =====
 DATABASE_MODE=auto
-POSTGRES_HOST=postgres
-POSTGRES_PORT=5432
-POSTGRES_DB=remnawave_bot
-POSTGRES_USER=remnawave_user
+POSTGRES_HOST=%s
+POSTGRES_PORT=%s
+POSTGRES_DB=%s
+POSTGRES_USER=%s
 POSTGRES_PASSWORD=%s
-REDIS_URL=redis://redis:6379/0
+%s
+REDIS_URL=%s
 
 # ===== REMNAWAVE API (ОБЯЗАТЕЛЬНО) =====
 REMNAWAVE_API_URL=%s
@@ -87,13 +118,16 @@ WEBHOOK_PATH=/webhook
 # ===== WEB API =====
 WEB_API_ENABLED=%s
 WEB_API_HOST=0.0.0.0
-WEB_API_PORT=8080
+WEB_API_PORT=%s
 WEB_API_DEFAULT_TOKEN=%s
 
 # ===== LOCALIZATION =====
-DEFAULT_LANGUAGE=ru
-AVAILABLE_LANGUAGES=ru,en,ua,zh,fa
-TZ=Europe/Moscow
+DEFAULT_LANGUAGE=%s
+AVAILABLE_LANGUAGES=%s
+TZ=%s
+
+# ===== INSTALLER (используется bedolaga_installer, не ботом) =====
+%s
 
 # ===== BASIC SETTINGS =====
 SALES_MODE=tariffs
@@ -219,6 +253,7 @@ DEFAULT_DEVICE_LIMIT=3
 #CONNECT_BUTTON_MODE=miniapp_subscription
 
 # ===== MINIAPP =====
+%s
 #MINIAPP_STATIC_PATH=miniapp
 #MINIAPP_SERVICE_NAME_RU=Bedolaga VPN
 
@@ -254,12 +289,17 @@ DEFAULT_DEVICE_LIMIT=3
 `,
 		appVersion, time.Now().Format("2006-01-02 15:04:05"),
 		cfg.BotToken, cfg.AdminIDs, cfg.SupportUsername,
-		cfg.PostgresPassword,
+		postgresHost(cfg), postgresPort(cfg), postgresDB(cfg), postgresUser(cfg),
+		cfg.PostgresPassword, envLine("POSTGRES_SSLMODE", cfg.PostgresSSLMode),
+		redisURL(cfg),
 		cfg.RemnawaveAPIURL, cfg.RemnawaveAPIKey, cfg.RemnawaveAuthType,
 		basicAuthLines, secretKeyLine,
 		cfg.BotRunMode, webhookURLLine, webhookSecretLine,
-		cfg.WebAPIEnabled, cfg.WebAPIDefaultToken,
+		cfg.WebAPIEnabled, webAPIPort(cfg), cfg.WebAPIDefaultToken,
+		orDefault(cfg.DefaultLanguage, defaultLanguage), orDefault(cfg.AvailableLanguages, defaultLanguages), timezoneOrDefault(cfg),
+		installerLines,
 		cabinetJWTSecret, adminNotifEnabled, adminNotifChatID,
+		miniappURLLine,
 	)
 
 	envPath := filepath.Join(cfg.InstallDir, ".env")
@@ -267,5 +307,170 @@ DEFAULT_DEVICE_LIMIT=3
 		globalProgress.fail("Ошибка записи .env: " + err.Error())
 		os.Exit(1)
 	}
+	registerEnvSecrets(readEnvFile(envPath))
 	globalProgress.done("Файл .env создан")
 }
+
+// ════════════════════════════════════════════════════════════════
+// ENV FILE READ / UPDATE
+// ════════════════════════════════════════════════════════════════
+
+// readEnvFile читает активные (не закомментированные) KEY=VALUE строки .env
+func readEnvFile(path string) map[string]string {
+	values := map[string]string{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+		values[strings.TrimSpace(key)] = val
+	}
+	return values
+}
+
+// secretEnvKeys — переменные .env, значения которых никогда не выводятся
+var secretEnvKeys = []string{
+	"BOT_TOKEN", "REMNAWAVE_API_KEY", "REMNAWAVE_PASSWORD", "REMNAWAVE_SECRET_KEY",
+	"POSTGRES_PASSWORD", "WEBHOOK_SECRET_TOKEN", "WEB_API_DEFAULT_TOKEN", "CABINET_JWT_SECRET",
+}
+
+// registerEnvSecrets регистрирует секреты из .env для ui.Redact (включая пароль в REDIS_URL)
+func registerEnvSecrets(env map[string]string) {
+	for _, key := range secretEnvKeys {
+		ui.RegisterSecret(env[key])
+	}
+	if u, err := url.Parse(env["REDIS_URL"]); err == nil && u.User != nil {
+		if pw, ok := u.User.Password(); ok {
+			ui.RegisterSecret(pw)
+		}
+	}
+}
+
+// updateEnvFile заменяет значения ключей (в том числе закомментированных #KEY=)
+// на месте, отсутствующие ключи дописывает в конец. Пустое значение
+// комментирует строку.
+func updateEnvFile(path string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	seen := map[string]bool{}
+	for i, line := range lines {
+		key, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "#"), "=")
+		key = strings.TrimSpace(key)
+		val, want := values[key]
+		if !ok || !want || seen[key] {
+			continue
+		}
+		// закомментированный шаблон трогаем, только если активной строки нет
+		if strings.HasPrefix(strings.TrimSpace(line), "#") && envHasActiveKey(lines, key) {
+			continue
+		}
+		lines[i] = envLine(key, val)
+		seen[key] = true
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, envLine(key, values[key]))
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+}
+
+func envHasActiveKey(lines []string, key string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), key+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// boolEnv — "true" или пусто (строка закомментируется)
+func boolEnv(b bool) string {
+	if b {
+		return "true"
+	}
+	return ""
+}
+
+func envLine(key, val string) string {
+	if val == "" {
+		return "#" + key + "="
+	}
+	return key + "=" + val
+}
+
+// loadInstallConfig восстанавливает конфигурацию установки из .env.
+// ok=false для установок без секции INSTALLER (созданных старыми версиями).
+func loadInstallConfig(installDir string) (cfg *Config, ok bool) {
+	env := readEnvFile(filepath.Join(installDir, ".env"))
+	cfg = &Config{
+		InstallDir:         installDir,
+		BotToken:           env["BOT_TOKEN"],
+		AdminIDs:           env["ADMIN_IDS"],
+		SupportUsername:    env["SUPPORT_USERNAME"],
+		RemnawaveAPIURL:    env["REMNAWAVE_API_URL"],
+		RemnawaveAPIKey:    env["REMNAWAVE_API_KEY"],
+		RemnawaveAuthType:  env["REMNAWAVE_AUTH_TYPE"],
+		RemnawaveUsername:  env["REMNAWAVE_USERNAME"],
+		RemnawavePassword:  env["REMNAWAVE_PASSWORD"],
+		RemnawaveSecretKey: env["REMNAWAVE_SECRET_KEY"],
+		PostgresPassword:   env["POSTGRES_PASSWORD"],
+		PostgresHost:       env["POSTGRES_HOST"],
+		PostgresPort:       env["POSTGRES_PORT"],
+		PostgresDB:         env["POSTGRES_DB"],
+		PostgresUser:       env["POSTGRES_USER"],
+		PostgresSSLMode:    env["POSTGRES_SSLMODE"],
+		RedisURL:           env["REDIS_URL"],
+		WebhookSecretToken: env["WEBHOOK_SECRET_TOKEN"],
+		WebAPIDefaultToken: env["WEB_API_DEFAULT_TOKEN"],
+		BotRunMode:         env["BOT_RUN_MODE"],
+		WebhookURL:         env["WEBHOOK_URL"],
+		WebAPIEnabled:      env["WEB_API_ENABLED"],
+		ReverseProxyType:   env["INSTALLER_REVERSE_PROXY"],
+		MiniappDomain:      env["INSTALLER_MINIAPP_DOMAIN"],
+		PanelDir:           env["INSTALLER_PANEL_DIR"],
+		DockerNetwork:      env["INSTALLER_DOCKER_NETWORK"],
+		WebAPIBind:         env["INSTALLER_WEB_API_BIND"],
+		WebAPIPort:         env["WEB_API_PORT"],
+		ResourceProfile:    env["INSTALLER_RESOURCE_PROFILE"],
+		BotImage:           env["INSTALLER_BOT_IMAGE"],
+		Offline:            env["INSTALLER_OFFLINE"] == "true",
+		HTTPProxy:          env["INSTALLER_HTTP_PROXY"],
+		NoProxy:            env["INSTALLER_NO_PROXY"],
+		RegistryMirror:     env["INSTALLER_REGISTRY_MIRROR"],
+		DockerDownloadURL:  env["INSTALLER_DOCKER_REPO"],
+		GitMirror:          env["INSTALLER_GIT_MIRROR"],
+		AptMirror:          env["INSTALLER_APT_MIRROR"],
+		LogMaxSize:         env["INSTALLER_LOG_MAX_SIZE"],
+		LogKeep:            env["INSTALLER_LOG_KEEP"],
+		LogMaxAge:          env["INSTALLER_LOG_MAX_AGE"],
+		Timezone:           env["TZ"],
+		DefaultLanguage:    env["DEFAULT_LANGUAGE"],
+		AvailableLanguages: env["AVAILABLE_LANGUAGES"],
+	}
+	cfg.PanelInstalledLocally = cfg.PanelDir != ""
+	if cfg.WebhookURL != "" {
+		cfg.WebhookDomain = cleanDomain(cfg.WebhookURL)
+	}
+	return cfg, cfg.ReverseProxyType != ""
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestEnv(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := writeTestEnv(t, "# comment\nBOT_TOKEN=123:abc\n#WEBHOOK_URL=\nQUOTED=\"a b\"\n\nEMPTY=\n")
+	env := readEnvFile(path)
+	if env["BOT_TOKEN"] != "123:abc" {
+		t.Errorf("BOT_TOKEN = %q", env["BOT_TOKEN"])
+	}
+	if env["QUOTED"] != "a b" {
+		t.Errorf("QUOTED = %q", env["QUOTED"])
+	}
+	if _, ok := env["WEBHOOK_URL"]; ok {
+		t.Error("commented key should be skipped")
+	}
+	if v, ok := env["EMPTY"]; !ok || v != "" {
+		t.Errorf("EMPTY = %q, %v", v, ok)
+	}
+}
+
+func TestUpdateEnvFile(t *testing.T) {
+	path := writeTestEnv(t, "BOT_RUN_MODE=polling\n#WEBHOOK_URL=\nWEB_API_ENABLED=true\n")
+	err := updateEnvFile(path, map[string]string{
+		"BOT_RUN_MODE":    "webhook",
+		"WEBHOOK_URL":     "https://bot.example.com",
+		"WEB_API_ENABLED": "",
+		"NEW_KEY":         "1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "BOT_RUN_MODE=webhook\nWEBHOOK_URL=https://bot.example.com\n#WEB_API_ENABLED=\nNEW_KEY=1\n"
+	if string(data) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestUpdateEnvFileKeepsCommentedDuplicate(t *testing.T) {
+	path := writeTestEnv(t, "#TZ=UTC\nTZ=Europe/Moscow\n")
+	if err := updateEnvFile(path, map[string]string{"TZ": "Asia/Tokyo"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "#TZ=UTC\nTZ=Asia/Tokyo\n" {
+		t.Errorf("got:\n%s", data)
+	}
+}
+
+func TestLoadInstallConfig(t *testing.T) {
+	dir := t.TempDir()
+	env := strings.Join([]string{
+		"WEBHOOK_URL=https://bot.example.com",
+		"INSTALLER_REVERSE_PROXY=caddy",
+		"INSTALLER_PANEL_DIR=/opt/remnawave",
+		"INSTALLER_DOCKER_NETWORK=remnawave-network",
+		"INSTALLER_WEB_API_BIND=127.0.0.1",
+	}, "\n")
+	os.WriteFile(filepath.Join(dir, ".env"), []byte(env), 0600)
+
+	cfg, ok := loadInstallConfig(dir)
+	if !ok {
+		t.Fatal("expected installer state to be found")
+	}
+	if !cfg.PanelInstalledLocally || cfg.DockerNetwork != "remnawave-network" {
+		t.Errorf("panel: %v %q", cfg.PanelInstalledLocally, cfg.DockerNetwork)
+	}
+	if cfg.WebhookDomain != "bot.example.com" || cfg.WebAPIBind != "127.0.0.1" {
+		t.Errorf("domain %q bind %q", cfg.WebhookDomain, cfg.WebAPIBind)
+	}
+
+	if _, ok := loadInstallConfig(t.TempDir()); ok {
+		t.Error("legacy install without INSTALLER_* keys should report ok=false")
+	}
+}
+
+func TestCreateEnvFileRoundTrip(t *testing.T) {
+	cfg := &Config{
+		InstallDir:         t.TempDir(),
+		BotToken:           "123:abc",
+		ReverseProxyType:   "caddy",
+		MiniappDomain:      "app.example.com",
+		WebAPIBind:         "127.0.0.1",
+		WebAPIPort:         "9090",
+		ResourceProfile:    "small",
+		Timezone:           "Asia/Tokyo",
+		DefaultLanguage:    "en",
+		AvailableLanguages: "en,ru",
+	}
+	createEnvFile(cfg)
+
+	loaded, ok := loadInstallConfig(cfg.InstallDir)
+	if !ok {
+		t.Fatal("installer section missing in generated .env")
+	}
+	checks := map[string][2]string{
+		"BotToken":           {loaded.BotToken, cfg.BotToken},
+		"MiniappDomain":      {loaded.MiniappDomain, cfg.MiniappDomain},
+		"WebAPIBind":         {loaded.WebAPIBind, cfg.WebAPIBind},
+		"WebAPIPort":         {loaded.WebAPIPort, cfg.WebAPIPort},
+		"ResourceProfile":    {loaded.ResourceProfile, cfg.ResourceProfile},
+		"Timezone":           {loaded.Timezone, cfg.Timezone},
+		"DefaultLanguage":    {loaded.DefaultLanguage, cfg.DefaultLanguage},
+		"AvailableLanguages": {loaded.AvailableLanguages, cfg.AvailableLanguages},
+	}
+	for name, v := range checks {
+		if v[0] != v[1] {
+			t.Errorf("%s = %q, want %q", name, v[0], v[1])
+		}
+	}
+}
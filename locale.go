@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// TIMEZONE & LANGUAGES
+// ════════════════════════════════════════════════════════════════
+
+const (
+	defaultTimezone  = "Europe/Moscow"
+	defaultLanguage  = "ru"
+	defaultLanguages = "ru,en,ua,zh,fa"
+)
+
+var supportedLanguages = []ui.SelectItem{
+	{Title: "ru", Description: "Русский"},
+	{Title: "en", Description: "English"},
+	{Title: "ua", Description: "Українська"},
+	{Title: "zh", Description: "中文"},
+	{Title: "fa", Description: "فارسی"},
+}
+
+// detectHostTimezone определяет часовой пояс хоста: /etc/timezone, timedatectl, /etc/localtime
+func detectHostTimezone() string {
+	if data, err := os.ReadFile("/etc/timezone"); err == nil {
+		if tz := strings.TrimSpace(string(data)); tz != "" {
+			return tz
+		}
+	}
+	if out, err := runShellSilent("timedatectl show -p Timezone --value 2>/dev/null"); err == nil && out != "" {
+		return out
+	}
+	if link, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, tz, ok := strings.Cut(link, "zoneinfo/"); ok {
+			return tz
+		}
+	}
+	return ""
+}
+
+func validTimezone(tz string) bool {
+	if tz == "" || strings.HasPrefix(tz, "/") || strings.Contains(tz, "..") {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+func timezoneOrDefault(cfg *Config) string {
+	return orDefault(cfg.Timezone, defaultTimezone)
+}
+
+// normalizeLanguages проверяет список языков через запятую; возвращает "" при ошибке
+func normalizeLanguages(list string) string {
+	var out []string
+	for _, lang := range strings.Split(list, ",") {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" {
+			continue
+		}
+		if !isSupportedLanguage(lang) {
+			return ""
+		}
+		if !containsString(out, lang) {
+			out = append(out, lang)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+// resolveLanguages проверяет язык по умолчанию и список доступных (пустой — все)
+// и добавляет язык по умолчанию в список, если его там нет
+func resolveLanguages(def, list string) (string, error) {
+	if !isSupportedLanguage(def) {
+		return "", fmt.Errorf("неизвестный язык %s, поддерживаются: %s", def, defaultLanguages)
+	}
+	if strings.TrimSpace(list) == "" {
+		list = defaultLanguages
+	}
+	langs := normalizeLanguages(list)
+	if langs == "" {
+		return "", fmt.Errorf("неверный список языков %s, поддерживаются: %s", list, defaultLanguages)
+	}
+	if !containsString(strings.Split(langs, ","), def) {
+		langs = def + "," + langs
+	}
+	return langs, nil
+}
+
+func isSupportedLanguage(lang string) bool {
+	for _, l := range supportedLanguages {
+		if l.Title == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func selectTimezone(cfg *Config) {
+	hostTZ := detectHostTimezone()
+	items := []ui.SelectItem{}
+	if hostTZ != "" && hostTZ != defaultTimezone {
+		items = append(items, ui.SelectItem{Title: hostTZ, Description: "Часовой пояс сервера"})
+	}
+	items = append(items,
+		ui.SelectItem{Title: defaultTimezone, Description: "Москва (по умолчанию)"},
+		ui.SelectItem{Title: "UTC", Description: "Всемирное координированное время"},
+		ui.SelectItem{Title: "Свой", Description: "Указать часовой пояс IANA, например Asia/Almaty"},
+	)
+	idx := ui.SelectOption("Часовой пояс", items)
+	if items[idx].Title != "Свой" {
+		cfg.Timezone = items[idx].Title
+		return
+	}
+	for {
+		tz := ui.InputText("Часовой пояс", "Europe/Berlin", "Формат IANA: Регион/Город", true)
+		if validTimezone(tz) {
+			cfg.Timezone = tz
+			return
+		}
+		ui.PrintError("Неизвестный часовой пояс: " + tz)
+		if !ui.IsInteractive() {
+			cfg.Timezone = defaultTimezone
+			return
+		}
+	}
+}
+
+func selectLanguages(cfg *Config) {
+	idx := ui.SelectOption("Язык бота по умолчанию", supportedLanguages)
+	cfg.DefaultLanguage = supportedLanguages[idx].Title
+
+	for {
+		val := ui.InputText("Доступные языки (необязательно)", defaultLanguages, "Через запятую. Оставьте пустым для всех: "+defaultLanguages, false)
+		langs, err := resolveLanguages(cfg.DefaultLanguage, val)
+		if err == nil {
+			cfg.AvailableLanguages = langs
+			return
+		}
+		ui.PrintError(err.Error())
+		if !ui.IsInteractive() {
+			cfg.AvailableLanguages = defaultLanguages
+			return
+		}
+	}
+}
+
+// selectLocalization — часовой пояс и языки бота
+func selectLocalization(cfg *Config) {
+	selectTimezone(cfg)
+	selectLanguages(cfg)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// LOG ROTATION (<install>/logs)
+// ════════════════════════════════════════════════════════════════
+
+const (
+	logrotateConfig   = "/etc/logrotate.d/bedolaga-bot"
+	defaultLogMaxSize = "50M"
+	defaultLogKeep    = "14"
+	defaultLogMaxAge  = "30"
+)
+
+var logSizeRe = regexp.MustCompile(`^[1-9][0-9]*[kKmMgG]?$`)
+
+// validLogRotation — размер в формате logrotate (10M, 500k), число архивов (0 — отключено)
+// и срок хранения архивов в днях
+func validLogRotation(size, keep, age string) bool {
+	n, err := strconv.Atoi(keep)
+	days, ageErr := strconv.Atoi(age)
+	return logSizeRe.MatchString(size) && err == nil && n >= 0 && n <= 365 &&
+		ageErr == nil && days > 0 && days <= 3650
+}
+
+func logRotationEnabled(cfg *Config) bool {
+	return orDefault(cfg.LogKeep, defaultLogKeep) != "0"
+}
+
+func describeLogRotation(cfg *Config) string {
+	if !logRotationEnabled(cfg) {
+		return "отключена"
+	}
+	return fmt.Sprintf("до %s, %s архивов не старше %s дн. (gzip)", orDefault(cfg.LogMaxSize, defaultLogMaxSize),
+		orDefault(cfg.LogKeep, defaultLogKeep), orDefault(cfg.LogMaxAge, defaultLogMaxAge))
+}
+
+// renderLogrotate — политика для logs/*.log: ежедневно или при превышении размера,
+// copytruncate — бот держит файлы открытыми и не умеет переоткрывать их по сигналу
+func renderLogrotate(installDir, size, keep, age string) string {
+	return fmt.Sprintf(`# Создано bedolaga_installer — bot config logs
+%s/logs/*.log {
+    su root root
+    daily
+    maxsize %s
+    rotate %s
+    maxage %s
+    missingok
+    notifempty
+    compress
+    delaycompress
+    copytruncate
+}
+`, installDir, size, keep, age)
+}
+
+// writeLogRotation устанавливает logrotate (если нужно) и пишет политику; keep=0 — удаляет её
+func writeLogRotation(cfg *Config) error {
+	if !logRotationEnabled(cfg) {
+		os.Remove(logrotateConfig)
+		return nil
+	}
+	if !commandExists("logrotate") {
+		if err := pkgManager().Install("logrotate"); err != nil {
+			return fmt.Errorf("не удалось установить logrotate: %v", err)
+		}
+	}
+	conf := renderLogrotate(cfg.InstallDir, orDefault(cfg.LogMaxSize, defaultLogMaxSize),
+		orDefault(cfg.LogKeep, defaultLogKeep), orDefault(cfg.LogMaxAge, defaultLogMaxAge))
+	if err := os.WriteFile(logrotateConfig, []byte(conf), 0644); err != nil {
+		return err
+	}
+	if out, err := runShellSilent("logrotate -d " + logrotateConfig + " 2>&1"); err != nil {
+		return fmt.Errorf("logrotate -d: %s", lastLine(out))
+	}
+	return nil
+}
+
+// selectLogRotation — выбор политики ротации logs/ в мастере
+func selectLogRotation(cfg *Config) {
+	idx := ui.SelectOption("Ротация логов бота (logs/)", []ui.SelectItem{
+		{Title: "Стандартная", Description: "До 50 МБ на файл, 14 архивов, хранение 30 дней, gzip"},
+		{Title: "Компактная", Description: "До 10 МБ на файл, 5 архивов, хранение 7 дней — для маленьких дисков"},
+		{Title: "Своя", Description: "Указать размер, число архивов и срок хранения"},
+		{Title: "Отключить", Description: "Логи не ротируются"},
+	})
+	switch idx {
+	case 0:
+		cfg.LogMaxSize, cfg.LogKeep, cfg.LogMaxAge = defaultLogMaxSize, defaultLogKeep, defaultLogMaxAge
+	case 1:
+		cfg.LogMaxSize, cfg.LogKeep, cfg.LogMaxAge = "10M", "5", "7"
+	case 2:
+		for {
+			size := ui.InputText("Максимальный размер файла", defaultLogMaxSize, "Например 100M или 500k", true)
+			keep := ui.InputText("Сколько архивов хранить", defaultLogKeep, "0 — отключить ротацию", true)
+			age := ui.InputText("Срок хранения архивов, дней", defaultLogMaxAge, "Архивы старше удаляются", true)
+			if validLogRotation(size, keep, age) {
+				cfg.LogMaxSize, cfg.LogKeep, cfg.LogMaxAge = size, keep, age
+				return
+			}
+			ui.PrintError("Неверные значения: " + size + ", " + keep + ", " + age)
+			if !ui.IsInteractive() {
+				cfg.LogMaxSize, cfg.LogKeep, cfg.LogMaxAge = defaultLogMaxSize, defaultLogKeep, defaultLogMaxAge
+				return
+			}
+		}
+	case 3:
+		cfg.LogMaxSize, cfg.LogKeep = defaultLogMaxSize, "0"
+	}
+}
+
+// setupLogRotation — шаг мастера после создания каталогов и .env
+func setupLogRotation(cfg *Config) {
+	if err := writeLogRotation(cfg); err != nil {
+		globalProgress.warn("Ротация логов не настроена: " + err.Error())
+		return
+	}
+	globalProgress.done("Ротация логов: " + describeLogRotation(cfg))
+}
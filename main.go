@@ -41,8 +41,12 @@ func main() {
                 manageBot()
         case "update", "upgrade":
                 updateBot()
+        case "preflight":
+                preflightCommand()
         case "uninstall", "remove":
                 uninstallBot()
+        case "bundle":
+                bundleCommand()
         case "version", "--version", "-v":
                 fmt.Println(lipgloss.NewStyle().Foreground(ui.ColorAccent).Bold(true).Render("bedolaga_installer") + " " + ui.DimStyle.Render("v"+appVersion))
         case "help", "--help", "-h":
@@ -52,6 +56,10 @@ func main() {
                 fmt.Println(ui.DimStyle.Render("    manage     ") + "Панель управления ботом (TUI)")
                 fmt.Println(ui.DimStyle.Render("    update     ") + "Обновить бота (git pull + пересборка)")
                 fmt.Println(ui.DimStyle.Render("    uninstall  ") + "Удалить бота")
+                fmt.Println(ui.DimStyle.Render("    preflight  ") + "Проверить сервер на соответствие требованиям")
+                fmt.Println(ui.DimStyle.Render("    bundle     ") + "bundle create — офлайн-бандл (репозиторий, образы, установщик)")
+                fmt.Println(ui.DimStyle.Render("    install --offline <bundle> ") + "Установка без доступа к сети")
+                fmt.Println(ui.DimStyle.Render("    install --proxy URL --registry-mirror URL ") + "Прокси и зеркала (также --no-proxy, --docker-repo, --git-mirror, --apt-mirror)")
                 fmt.Println(ui.DimStyle.Render("    version    ") + "Показать версию")
                 fmt.Println(ui.DimStyle.Render("    help       ") + "Показать эту справку")
                 fmt.Println()
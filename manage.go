@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -33,6 +34,7 @@ func manageBot() {
 		os.Exit(1)
 	}
 	composeFile := detectComposeFile(installDir)
+	registerEnvSecrets(readEnvFile(filepath.Join(installDir, ".env")))
 
 	// Прямые субкоманды: bot logs, bot status, etc.
 	if len(os.Args) > 2 {
@@ -54,6 +56,8 @@ func manageBot() {
 			{Title: "Бэкап", Description: "Резервная копия БД и конфигурации"},
 			{Title: "Диагностика", Description: "Проверка работоспособности всех компонентов"},
 			{Title: "Конфигурация", Description: "Открыть .env в редакторе"},
+			{Title: "Ресурсы", Description: "Профиль ресурсов PostgreSQL/Redis и лимиты контейнеров"},
+			{Title: "Очистка", Description: "Старые образы, кэш сборки, бэкапы и ротированные логи"},
 			{Title: "Удаление", Description: "Полное удаление бота и контейнеров"},
 			{Title: "Выход", Description: "Закрыть панель управления"},
 		})
@@ -74,7 +78,7 @@ func manageBot() {
 			manageStop(installDir, composeFile)
 			waitForEnter()
 		case 5:
-			manageUpdate(installDir, composeFile)
+			manageUpdate(installDir, composeFile, nil)
 			waitForEnter()
 		case 6:
 			manageBackup(installDir, composeFile)
@@ -83,8 +87,14 @@ func manageBot() {
 			manageHealth(installDir, composeFile)
 			waitForEnter()
 		case 8:
-			manageConfig(installDir)
+			manageConfig(installDir, composeFile, nil)
 		case 9:
+			manageTune(installDir, composeFile)
+			waitForEnter()
+		case 10:
+			manageCleanup(installDir, nil)
+			waitForEnter()
+		case 11:
 			manageUninstall(installDir, composeFile)
 			return
 		default:
@@ -128,7 +138,7 @@ func manageLogs(installDir, composeFile string) {
 	ui.PrintInfo("Логи бота (Ctrl+C для выхода)...")
 	fmt.Println()
 	allowExit = true
-	runShell(fmt.Sprintf("cd %s && docker compose -f %s logs -f --tail=150 bot", installDir, composeFile))
+	runShell(composeCmd(installDir, composeFile) + " logs -f --tail=150 bot")
 	allowExit = false
 }
 
@@ -142,7 +152,7 @@ func manageStatus(installDir, composeFile string) {
 	fmt.Println()
 	fmt.Println(ui.HighlightStyle.Render("  Контейнеры"))
 	fmt.Println(sep)
-	out, _ := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s ps --format 'table {{.Name}}\\t{{.Status}}\\t{{.Ports}}' 2>/dev/null", installDir, composeFile))
+	out, _ := runShellSilent(composeCmd(installDir, composeFile) + " ps --format 'table {{.Name}}\\t{{.Status}}\\t{{.Ports}}' 2>/dev/null")
 	if out != "" {
 		for _, line := range strings.Split(out, "\n") {
 			if strings.Contains(strings.ToLower(line), "up") || strings.Contains(line, "NAME") {
@@ -184,7 +194,7 @@ func manageStatus(installDir, composeFile string) {
 
 func manageRestart(installDir, composeFile string) {
 	ui.RunWithSpinner("Перезапуск контейнеров...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s restart", installDir, composeFile))
+		_, err := runShellSilent(composeCmd(installDir, composeFile) + " restart")
 		return err
 	})
 	ui.PrintSuccess("Контейнеры перезапущены")
@@ -192,7 +202,7 @@ func manageRestart(installDir, composeFile string) {
 
 func manageStart(installDir, composeFile string) {
 	ui.RunWithSpinner("Запуск контейнеров...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s up -d", installDir, composeFile))
+		_, err := runShellSilent(composeCmd(installDir, composeFile) + " up -d")
 		return err
 	})
 	ui.PrintSuccess("Контейнеры запущены")
@@ -203,7 +213,7 @@ func manageStop(installDir, composeFile string) {
 		return
 	}
 	ui.RunWithSpinner("Остановка контейнеров...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s down", installDir, composeFile))
+		_, err := runShellSilent(composeCmd(installDir, composeFile) + " down")
 		return err
 	})
 	ui.PrintSuccess("Контейнеры остановлены")
@@ -213,7 +223,7 @@ func manageStop(installDir, composeFile string) {
 // MANAGE: UPDATE
 // ════════════════════════════════════════════════════════════════
 
-func manageUpdate(installDir, composeFile string) {
+func manageUpdate(installDir, composeFile string, args []string) {
 	if !ui.ConfirmPrompt("Начать обновление бота?", true) {
 		return
 	}
@@ -221,15 +231,32 @@ func manageUpdate(installDir, composeFile string) {
 	runShellSilent(fmt.Sprintf(`cd %s && cp .env ".env.backup_$(date +%%Y%%m%%d_%%H%%M%%S)" 2>/dev/null || true`, installDir))
 	ui.PrintSuccess("Резервная копия .env создана")
 
-	ui.RunWithSpinner("Загрузка обновлений...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && git pull origin main", installDir))
-		return err
-	})
+	if err := applyUpdateImageArgs(installDir, args); err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+
+	if err := ui.RunWithSpinner("Загрузка обновлений...", func() error {
+		return pullBotRepo(installDir)
+	}); err != nil {
+		ui.PrintWarning(err.Error())
+	}
 
-	ui.RunWithSpinner("Пересборка контейнеров...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s down && docker compose -f %s up -d --build", installDir, composeFile, composeFile))
+	cfg, _ := loadInstallConfig(installDir)
+	composeFile = detectComposeFile(installDir)
+	title := "Пересборка контейнеров..."
+	if cfg.BotImage != "" {
+		title = "Загрузка образа " + cfg.BotImage + "..."
+	}
+	var fellBack bool
+	ui.RunWithSpinner(title, func() error {
+		var err error
+		fellBack, err = updateBotStack(cfg, composeFile)
 		return err
 	})
+	if fellBack {
+		ui.PrintWarning("Образ " + cfg.BotImage + " недоступен — бот собран из исходников")
+	}
 
 	ui.PrintSuccess("Обновление завершено")
 }
@@ -241,21 +268,24 @@ func manageUpdate(installDir, composeFile string) {
 func manageBackup(installDir, composeFile string) {
 	timestamp := time.Now().Format("20060102_150405")
 	backupDir := filepath.Join(installDir, "data", "backups", timestamp)
-	os.MkdirAll(backupDir, 0755)
+	os.MkdirAll(backupDir, secretDirMode)
 
 	fmt.Println()
 
+	cfg, _ := loadInstallConfig(installDir)
 	ui.RunWithSpinner("Бэкап базы данных...", func() error {
-		_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s exec -T postgres pg_dump -U remnawave_user remnawave_bot > %s/database.sql 2>/dev/null", installDir, composeFile, backupDir))
+		cmd, env := pgClientCmd(cfg, composeFile, "pg_dump")
+		_, err := runShellSilentEnv(fmt.Sprintf("%s %s > %s/database.sql 2>/dev/null", cmd, shellQuote(postgresDB(cfg)), backupDir), env...)
 		return err
 	})
 
 	runShellSilent(fmt.Sprintf("cp %s/.env %s/.env 2>/dev/null", installDir, backupDir))
 	runShellSilent(fmt.Sprintf("cp %s/docker-compose*.yml %s/ 2>/dev/null", installDir, backupDir))
+	runShellSilent(fmt.Sprintf("chmod 600 %s/* 2>/dev/null", backupDir))
 
 	ui.PrintSuccess("Бэкап создан: " + backupDir)
 
-	runShellSilent(fmt.Sprintf(`ls -dt "%s/data/backups"/*/ 2>/dev/null | tail -n +6 | xargs -r rm -rf`, installDir))
+	runShellSilent(fmt.Sprintf(`ls -dt "%s/data/backups"/*/ 2>/dev/null | tail -n +%d | xargs -r rm -rf`, installDir, keepBackups+1))
 
 	out, _ := runShellSilent(fmt.Sprintf("du -sh %s 2>/dev/null | awk '{print $1}'", backupDir))
 	if out != "" {
@@ -282,20 +312,37 @@ func manageHealth(installDir, composeFile string) {
 		ui.PrintError("Бот: не запущен")
 	}
 
-	_, err := runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s exec -T postgres pg_isready -U remnawave_user 2>/dev/null", installDir, composeFile))
+	cfg, _ := loadInstallConfig(installDir)
+	pgLabel, redisLabel := "PostgreSQL", "Redis"
+	if externalPostgres(cfg) {
+		pgLabel = "PostgreSQL (внешний " + postgresHost(cfg) + ")"
+	}
+	if externalRedis(cfg) {
+		redisLabel = "Redis (внешний)"
+	}
+
+	pgCmd, pgEnv := pgClientCmd(cfg, composeFile, "pg_isready")
+	_, err := runShellSilentEnv(fmt.Sprintf("%s -d %s 2>/dev/null", pgCmd, shellQuote(postgresDB(cfg))), pgEnv...)
 	if err == nil {
-		ui.PrintSuccess("PostgreSQL: работает")
+		ui.PrintSuccess(pgLabel + ": работает")
 	} else {
-		ui.PrintError("PostgreSQL: не доступен")
+		ui.PrintError(pgLabel + ": не доступен")
 	}
 
-	_, err = runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s exec -T redis redis-cli ping 2>/dev/null", installDir, composeFile))
+	redisCmd, redisEnv, err := redisPingCmd(cfg, composeFile)
+	if err == nil {
+		_, err = runShellSilentEnv(redisCmd+" 2>/dev/null", redisEnv...)
+	}
 	if err == nil {
-		ui.PrintSuccess("Redis: работает")
+		ui.PrintSuccess(redisLabel + ": работает")
 	} else {
-		ui.PrintError("Redis: не доступен")
+		ui.PrintError(redisLabel + ": не доступен")
 	}
 
+	checkWebAPIExposure(installDir)
+	checkBotToken(installDir)
+	checkPanelAPI(cfg)
+
 	if commandExists("docker") {
 		ui.PrintSuccess("Docker: установлен")
 	} else {
@@ -315,20 +362,85 @@ func manageHealth(installDir, composeFile string) {
 	fmt.Println()
 	fmt.Println(ui.DimStyle.Render("  Последние логи:"))
 	fmt.Println(sep)
-	runShell(fmt.Sprintf("cd %s && docker compose -f %s logs --tail=10 bot 2>/dev/null", installDir, composeFile))
+	runShell(composeCmd(installDir, composeFile) + " logs --tail=10 bot 2>/dev/null")
+}
+
+// checkBotToken проверяет BOT_TOKEN из .env через getMe
+func checkBotToken(installDir string) {
+	token := readEnvFile(filepath.Join(installDir, ".env"))["BOT_TOKEN"]
+	if token == "" {
+		ui.PrintError("BOT_TOKEN: не задан")
+		return
+	}
+	if !validBotToken(token) {
+		ui.PrintWarning("BOT_TOKEN: необычный формат, проверка через Telegram")
+	}
+	bot, err := telegramGetMe(token)
+	var apiErr *telegramAPIError
+	switch {
+	case err == nil:
+		ui.PrintSuccess("BOT_TOKEN: действителен, " + bot.String())
+	case errors.As(err, &apiErr):
+		ui.PrintError("BOT_TOKEN: отклонён Telegram — " + apiErr.Description)
+	default:
+		ui.PrintWarning("BOT_TOKEN: Telegram недоступен — " + err.Error())
+	}
+}
+
+// checkWebAPIExposure предупреждает, если порт web API опубликован на всех
+// интерфейсах и доступен напрямую, в обход обратного прокси и TLS
+func checkWebAPIExposure(installDir string) {
+	cfg, _ := loadInstallConfig(installDir)
+	out, err := runShellSilent(fmt.Sprintf("docker port remnawave_bot %s/tcp 2>/dev/null", webAPIPort(cfg)))
+	if err != nil || out == "" {
+		return
+	}
+	if binding := publicBinding(out); binding != "" {
+		ui.PrintWarning("Web API: порт опубликован публично (" + binding + ") — доступен в обход прокси")
+		ui.PrintDim("Исправить: " + webAPIExposureFix(cfg))
+		return
+	}
+	ui.PrintSuccess("Web API: доступен только локально (" + strings.ReplaceAll(out, "\n", ", ") + ")")
 }
 
 // ════════════════════════════════════════════════════════════════
 // MANAGE: CONFIG
 // ════════════════════════════════════════════════════════════════
 
-func manageConfig(installDir string) {
+func manageConfig(installDir, composeFile string, args []string) {
 	envPath := filepath.Join(installDir, ".env")
 	if !fileExists(envPath) {
 		ui.PrintError("Файл .env не найден: " + envPath)
 		return
 	}
 
+	setting := ""
+	if len(args) > 0 {
+		setting = args[0]
+	} else if ui.IsInteractive() {
+		items := []ui.SelectItem{
+			{Title: "Редактировать .env", Description: "Открыть .env в редакторе ($EDITOR)"},
+			{Title: "Часовой пояс", Description: "TZ бота и контейнеров"},
+			{Title: "Языки", Description: "DEFAULT_LANGUAGE и AVAILABLE_LANGUAGES"},
+			{Title: "Ротация логов", Description: "Размер, число и срок хранения архивов logs/ (logrotate)"},
+		}
+		switch ui.SelectOption("Конфигурация", items) {
+		case 1:
+			setting = "timezone"
+		case 2:
+			setting = "language"
+		case 3:
+			setting = "logs"
+		}
+	}
+	if setting != "" {
+		manageConfigSetting(installDir, composeFile, setting, args)
+		if len(args) == 0 {
+			waitForEnter()
+		}
+		return
+	}
+
 	editor := os.Getenv("EDITOR")
 	if editor == "" {
 		editor = "nano"
@@ -345,6 +457,113 @@ func manageConfig(installDir string) {
 	waitForEnter()
 }
 
+// manageConfigSetting меняет отдельную настройку: bot config timezone|language [значение]
+func manageConfigSetting(installDir, composeFile, setting string, args []string) {
+	cfg, ok := loadInstallConfig(installDir)
+	value := ""
+	if len(args) > 1 {
+		value = args[1]
+	}
+
+	updates := map[string]string{}
+	switch setting {
+	case "timezone", "tz":
+		if value == "" {
+			selectTimezone(cfg)
+		} else if validTimezone(value) {
+			cfg.Timezone = value
+		} else {
+			ui.PrintError("Неизвестный часовой пояс: " + value)
+			return
+		}
+		updates["TZ"] = cfg.Timezone
+	case "language", "languages", "lang":
+		if value == "" {
+			selectLanguages(cfg)
+		} else {
+			list := cfg.AvailableLanguages
+			if len(args) > 2 {
+				list = args[2]
+			}
+			langs, err := resolveLanguages(strings.ToLower(value), list)
+			if err != nil {
+				ui.PrintError(err.Error())
+				return
+			}
+			cfg.DefaultLanguage, cfg.AvailableLanguages = strings.ToLower(value), langs
+		}
+		updates["DEFAULT_LANGUAGE"] = cfg.DefaultLanguage
+		updates["AVAILABLE_LANGUAGES"] = cfg.AvailableLanguages
+	case "logs", "logrotate":
+		var rest []string
+		if len(args) > 1 {
+			rest = args[1:]
+		}
+		manageLogRotation(installDir, cfg, rest)
+		return
+	default:
+		ui.PrintError("Неизвестная настройка: " + setting)
+		ui.PrintDim("Доступно: bot config timezone <TZ>, bot config language <язык> [список], bot config logs <размер> <архивов> [дней]|off")
+		return
+	}
+
+	if err := updateEnvFile(filepath.Join(installDir, ".env"), updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	ui.PrintSuccess(".env обновлён")
+
+	// TZ зашит в сгенерированный compose — пересоздаём его
+	if ok && updates["TZ"] != "" {
+		composeFile = writeBotCompose(cfg)
+	} else if updates["TZ"] != "" {
+		ui.PrintWarning("Установка без секции INSTALLER: TZ в " + composeFile + " нужно изменить вручную")
+	}
+	var out string
+	err := ui.RunWithSpinner("Применение настроек...", func() error {
+		var err error
+		out, err = runShellSilent(composeCmd(installDir, composeFile) + " up -d 2>&1")
+		return err
+	})
+	if err != nil {
+		ui.PrintError("Настройки не применены: " + lastLine(out))
+		return
+	}
+	ui.PrintSuccess("Настройки применены")
+}
+
+// manageLogRotation — bot config logs [размер архивов [дней] | off]
+func manageLogRotation(installDir string, cfg *Config, args []string) {
+	switch {
+	case len(args) == 0:
+		ui.PrintInfo("Сейчас: " + describeLogRotation(cfg))
+		selectLogRotation(cfg)
+	case args[0] == "off":
+		cfg.LogKeep = "0"
+	case len(args) == 2 && validLogRotation(args[0], args[1], orDefault(cfg.LogMaxAge, defaultLogMaxAge)):
+		cfg.LogMaxSize, cfg.LogKeep = args[0], args[1]
+	case len(args) == 3 && validLogRotation(args[0], args[1], args[2]):
+		cfg.LogMaxSize, cfg.LogKeep, cfg.LogMaxAge = args[0], args[1], args[2]
+	default:
+		ui.PrintError("Использование: bot config logs <размер> <архивов> [дней] (например 50M 14 30) или bot config logs off")
+		return
+	}
+	updates := map[string]string{
+		"INSTALLER_LOG_MAX_SIZE": cfg.LogMaxSize,
+		"INSTALLER_LOG_KEEP":     cfg.LogKeep,
+		"INSTALLER_LOG_MAX_AGE":  cfg.LogMaxAge,
+	}
+	if err := updateEnvFile(filepath.Join(installDir, ".env"), updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	if err := writeLogRotation(cfg); err != nil {
+		ui.PrintError("Ротация логов не настроена: " + err.Error())
+		return
+	}
+	ui.PrintSuccess("Ротация логов: " + describeLogRotation(cfg))
+}
+
 // ════════════════════════════════════════════════════════════════
 // MANAGE: UNINSTALL
 // ════════════════════════════════════════════════════════════════
@@ -368,23 +587,26 @@ func manageUninstall(installDir, composeFile string) {
 	}
 
 	if ui.ConfirmPrompt("Создать резервную копию перед удалением?", true) {
-		runShellSilent(fmt.Sprintf(`cd %s && tar -czf "/root/bedolaga_backup_$(date +%%Y%%m%%d_%%H%%M%%S).tar.gz" .env data/ 2>/dev/null || true`, installDir))
+		runShellSilent(fmt.Sprintf(`cd %s && umask 077 && tar -czf "/root/bedolaga_backup_$(date +%%Y%%m%%d_%%H%%M%%S).tar.gz" .env data/ 2>/dev/null || true`, installDir))
 		ui.PrintSuccess("Резервная копия сохранена в /root/")
 	}
 
 	ui.RunWithSpinner("Остановка контейнеров...", func() error {
-		runShellSilent(fmt.Sprintf("cd %s && docker compose -f %s down -v 2>/dev/null || true", installDir, composeFile))
+		runShellSilent(composeCmd(installDir, composeFile) + " down -v 2>/dev/null || true")
 		return nil
 	})
 
 	runShellSilent("rm -f /etc/nginx/sites-enabled/bedolaga-* /etc/nginx/sites-available/bedolaga-*")
-	runShellSilent("nginx -t 2>/dev/null && systemctl reload nginx 2>/dev/null || true")
+	runShellSilent("rm -f /etc/nginx/conf.d/bedolaga-sites.conf /etc/nginx/http.d/bedolaga-sites.conf")
+	runShellSilent("nginx -t 2>/dev/null && " + serviceCmd("reload", "nginx") + " 2>/dev/null || true")
 	if fileExists("/etc/caddy/Caddyfile") {
 		runShellSilent(`sed -i '/# === BEGIN Bedolaga Bot ===/,/# === END Bedolaga Bot ===/d' /etc/caddy/Caddyfile`)
-		runShellSilent("systemctl reload caddy 2>/dev/null || true")
+		runShellSilent(serviceCmd("reload", "caddy") + " 2>/dev/null || true")
 	}
 
 	os.Remove("/usr/local/bin/bot")
+	os.Remove(logrotateConfig)
+	offerSwapRemoval()
 
 	if ui.ConfirmPrompt("Удалить каталог "+installDir+"?", false) {
 		os.RemoveAll(installDir)
@@ -413,13 +635,29 @@ func manageSubcommand(subcmd, installDir, composeFile string) {
 	case "stop":
 		manageStop(installDir, composeFile)
 	case "update", "upgrade":
-		manageUpdate(installDir, composeFile)
+		manageUpdate(installDir, composeFile, subcommandArgs())
 	case "backup":
 		manageBackup(installDir, composeFile)
 	case "health", "check":
 		manageHealth(installDir, composeFile)
 	case "config", "edit":
-		manageConfig(installDir)
+		manageConfig(installDir, composeFile, subcommandArgs())
+	case "tune":
+		manageTune(installDir, composeFile)
+	case "rotate-secrets":
+		manageRotateSecrets(installDir, composeFile, subcommandArgs())
+	case "audit":
+		manageAudit(installDir, subcommandArgs())
+	case "webhook":
+		manageWebhook(installDir, subcommandArgs())
+	case "mode":
+		manageMode(installDir, composeFile, subcommandArgs())
+	case "domain", "domains":
+		manageDomain(installDir, composeFile, subcommandArgs())
+	case "doctor":
+		manageDoctor(installDir, composeFile, subcommandArgs())
+	case "cleanup", "prune":
+		manageCleanup(installDir, subcommandArgs())
 	case "uninstall", "remove":
 		manageUninstall(installDir, composeFile)
 	case "help", "--help", "-h":
@@ -432,6 +670,14 @@ func manageSubcommand(subcmd, installDir, composeFile string) {
 	}
 }
 
+// subcommandArgs — аргументы после субкоманды: bot <subcmd> [args...]
+func subcommandArgs() []string {
+	if len(os.Args) > 3 {
+		return os.Args[3:]
+	}
+	return nil
+}
+
 func printManageHelp() {
 	ui.PrintBanner(appVersion)
 	fmt.Println(ui.HighlightStyle.Render("  Использование:") + ui.DimStyle.Render(" bot [команда]"))
@@ -443,10 +689,23 @@ func printManageHelp() {
 	fmt.Println(ui.InfoStyle.Render("  restart         ") + "  Перезапуск контейнеров")
 	fmt.Println(ui.InfoStyle.Render("  start           ") + "  Запуск контейнеров")
 	fmt.Println(ui.InfoStyle.Render("  stop            ") + "  Остановка контейнеров")
-	fmt.Println(ui.InfoStyle.Render("  update          ") + "  Обновление (git pull + rebuild)")
+	fmt.Println(ui.InfoStyle.Render("  update          ") + "  Обновление (git pull + rebuild или pull образа)")
+	fmt.Println(ui.InfoStyle.Render("  update --image  ") + "  Перейти на готовый образ: bot update --image <образ:тег>")
+	fmt.Println(ui.InfoStyle.Render("  update --build  ") + "  Вернуться к сборке из исходников")
 	fmt.Println(ui.InfoStyle.Render("  backup          ") + "  Создать резервную копию")
 	fmt.Println(ui.InfoStyle.Render("  health          ") + "  Диагностика системы")
 	fmt.Println(ui.InfoStyle.Render("  config          ") + "  Редактировать .env")
+	fmt.Println(ui.InfoStyle.Render("  config timezone ") + "  Часовой пояс: bot config timezone Europe/Berlin")
+	fmt.Println(ui.InfoStyle.Render("  config language ") + "  Языки: bot config language en ru,en")
+	fmt.Println(ui.InfoStyle.Render("  config logs     ") + "  Ротация logs/: bot config logs 50M 14 30 | off")
+	fmt.Println(ui.InfoStyle.Render("  tune            ") + "  Профиль ресурсов (после апгрейда сервера)")
+	fmt.Println(ui.InfoStyle.Render("  rotate-secrets  ") + "  Новые секреты: [--postgres] [--webhook] [--webapi] [--jwt] | --all")
+	fmt.Println(ui.InfoStyle.Render("  audit [--json]  ") + "  Аудит безопасности установки")
+	fmt.Println(ui.InfoStyle.Render("  webhook         ") + "  Вебхук Telegram: status | set [url] | delete [--drop-pending] | test")
+	fmt.Println(ui.InfoStyle.Render("  mode            ") + "  Режим бота: bot mode webhook --domain bot.example.com | bot mode polling")
+	fmt.Println(ui.InfoStyle.Render("  domain          ") + "  Домены: bot domain set webhook|miniapp <домен> | bot domain remove webhook|miniapp")
+	fmt.Println(ui.InfoStyle.Render("  doctor          ") + "  Проверка прав каталогов (--fix-permissions — исправить)")
+	fmt.Println(ui.InfoStyle.Render("  cleanup [--yes] ") + "  Очистка: образы, кэш сборки, старые бэкапы, ротированные логи")
 	fmt.Println(ui.InfoStyle.Render("  uninstall       ") + "  Удалить бота")
 	fmt.Println(ui.InfoStyle.Render("  help            ") + "  Эта справка")
 	fmt.Println()
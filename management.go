@@ -34,7 +34,8 @@ func printFinalInfo(cfg *Config) {
 	var b strings.Builder
 	b.WriteString(ui.SuccessStyle.Render("  УСТАНОВКА ЗАВЕРШЕНА") + "\n\n")
 	b.WriteString(ui.HighlightStyle.Render("  Каталог: ") + ui.InfoStyle.Render(cfg.InstallDir) + "\n")
-	b.WriteString(ui.HighlightStyle.Render("  Конфиг:  ") + ui.InfoStyle.Render(cfg.InstallDir+"/.env") + "\n\n")
+	b.WriteString(ui.HighlightStyle.Render("  Конфиг:  ") + ui.InfoStyle.Render(cfg.InstallDir+"/.env") + "\n")
+	b.WriteString(ui.HighlightStyle.Render("  Свои изменения: ") + ui.DimStyle.Render(composeOverrideFile+" (не перезаписывается)") + "\n\n")
 
 	b.WriteString(ui.HighlightStyle.Render("  Управление:") + "\n")
 	b.WriteString(ui.DimStyle.Render("    bot          ") + "Интерактивное меню\n")
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: RUN MODE (polling / webhook)
+// ════════════════════════════════════════════════════════════════
+
+const modeUsage = "Использование: bot mode webhook [--domain bot.example.com] | bot mode polling"
+
+// parseModeArgs разбирает: webhook [--domain X] | polling
+func parseModeArgs(args []string) (mode, domain string, err error) {
+	if len(args) == 0 {
+		return "", "", fmt.Errorf("не указан режим")
+	}
+	mode = args[0]
+	if mode != "webhook" && mode != "polling" {
+		return "", "", fmt.Errorf("неизвестный режим: %s", mode)
+	}
+	for i := 1; i < len(args); i++ {
+		switch {
+		case args[i] == "--domain" && i+1 < len(args):
+			domain = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--domain="):
+			domain = strings.TrimPrefix(args[i], "--domain=")
+		default:
+			return "", "", fmt.Errorf("неизвестный аргумент: %s", args[i])
+		}
+	}
+	if domain != "" && mode == "polling" {
+		return "", "", fmt.Errorf("--domain используется только с webhook")
+	}
+	return mode, strings.ToLower(cleanDomain(domain)), nil
+}
+
+// recreateBot пересоздаёт контейнеры, чтобы бот прочитал новый .env
+func recreateBot(installDir, composeFile string) error {
+	return ui.RunWithSpinner("Перезапуск бота...", func() error {
+		out, err := runShellSilent(composeCmd(installDir, composeFile) + " up -d 2>&1")
+		if err != nil {
+			return fmt.Errorf("%s", lastLine(out))
+		}
+		return nil
+	})
+}
+
+const botStartTimeout = 90 * time.Second
+
+// botReady — бот поднялся: healthcheck контейнера или /health web API отвечают
+func botReady(cfg *Config) (bool, error) {
+	out, _ := runShellSilent(`docker inspect -f '{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}}' remnawave_bot 2>/dev/null`)
+	state, health, _ := strings.Cut(out, " ")
+	switch {
+	case health == "healthy":
+		return true, nil
+	case state == "exited" || state == "dead":
+		return false, fmt.Errorf("контейнер бота остановлен — смотрите bot logs")
+	}
+	resp, err := (&http.Client{Timeout: 3 * time.Second}).Get("http://" + webAPIUpstream(cfg) + "/health")
+	if err != nil {
+		return false, nil
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// waitForBot ждёт запуска бота после пересоздания контейнера
+func waitForBot(cfg *Config) error {
+	return ui.RunWithSpinner("Ожидание запуска бота...", func() error {
+		deadline := time.Now().Add(botStartTimeout)
+		for {
+			ready, err := botReady(cfg)
+			if ready || err != nil {
+				return err
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("бот не ответил за %d с", int(botStartTimeout.Seconds()))
+			}
+			time.Sleep(2 * time.Second)
+		}
+	})
+}
+
+// registerWebhook регистрирует адрес из .env в Telegram, когда перезапущенный бот поднялся
+// (при запуске бот сам обращается к Telegram и мог бы перебить регистрацию)
+func registerWebhook(cfg *Config) {
+	env := readEnvFile(filepath.Join(cfg.InstallDir, ".env"))
+	hookURL := telegramWebhookURL(env)
+	if err := waitForBot(cfg); err != nil {
+		ui.PrintWarning("Бот не готов: " + err.Error())
+	}
+	if err := setTelegramWebhook(env["BOT_TOKEN"], hookURL, env["WEBHOOK_SECRET_TOKEN"]); err != nil {
+		ui.PrintError("Вебхук не зарегистрирован: " + err.Error())
+		ui.PrintDim("Повторить: bot webhook set")
+		return
+	}
+	ui.PrintSuccess("Вебхук зарегистрирован: " + hookURL)
+}
+
+// resolveDomain проверяет домен из аргумента (формат, DNS), как при установке;
+// пустой домен или «попробовать снова» — запрос домена. "" — отказ
+func resolveDomain(domain, label string) string {
+	if domain != "" {
+		checked, retry := checkDomainSafe(domain)
+		if !retry {
+			return checked
+		}
+	}
+	return inputDomainSafe(label, "Домен должен указывать на этот сервер")
+}
+
+// offerCertificateRemoval предлагает удалить сертификат домена, который
+// больше не обслуживается ни вебхуком, ни Mini App (Caddy хранит свои сертификаты сам)
+func offerCertificateRemoval(cfg *Config, old string) {
+	if old == "" || old == cfg.WebhookDomain || old == cfg.MiniappDomain || cfg.ReverseProxyType == "caddy" {
+		return
+	}
+	if dirExists("/etc/letsencrypt/live/"+old) && ui.ConfirmPrompt("Удалить сертификат "+old+"?", true) {
+		deleteCertificate(old)
+	}
+}
+
+// ensureReverseProxy выбирает прокси, если при установке он был пропущен
+func ensureReverseProxy(cfg *Config) bool {
+	if cfg.ReverseProxyType != "" && cfg.ReverseProxyType != "skip" {
+		return true
+	}
+	selectReverseProxy(cfg)
+	if cfg.ReverseProxyType == "skip" {
+		ui.PrintWarning("Обратный прокси не выбран — настройте HTTPS для домена вручную (порт web API " + webAPIPort(cfg) + ")")
+		return false
+	}
+	checkProxyPorts(cfg)
+	return true
+}
+
+func manageMode(installDir, composeFile string, args []string) {
+	mode, domain, err := parseModeArgs(args)
+	if err != nil {
+		ui.PrintError(err.Error())
+		ui.PrintDim(modeUsage)
+		return
+	}
+	envPath := filepath.Join(installDir, ".env")
+	cfg, _ := loadInstallConfig(installDir)
+	if cfg.MiniappDomain == "" {
+		cfg.MiniappDomain = miniappDomainFromProxy(cfg)
+	}
+	token := readEnvFile(envPath)["BOT_TOKEN"]
+
+	fmt.Println()
+	if mode == "polling" {
+		switchToPolling(cfg, composeFile, token)
+	} else {
+		switchToWebhook(cfg, composeFile, domain)
+	}
+}
+
+func switchToWebhook(cfg *Config, composeFile, domain string) {
+	envPath := filepath.Join(cfg.InstallDir, ".env")
+	old := cfg.WebhookDomain
+	if domain == "" {
+		domain = old
+	}
+	if domain == "" && !ui.IsInteractive() {
+		ui.PrintError("Укажите домен: " + modeUsage)
+		return
+	}
+	if domain = resolveDomain(domain, "Домен вебхука"); domain == "" {
+		return
+	}
+	cfg.WebhookDomain = domain
+
+	if ensureReverseProxy(cfg) {
+		if err := updateReverseProxy(cfg, domain); err != nil {
+			ui.PrintError("Прокси не обновлён: " + err.Error())
+			ui.PrintDim(".env не изменён, бот продолжает работать в прежнем режиме")
+			return
+		}
+		offerCertificateRemoval(cfg, old)
+	}
+
+	updates := map[string]string{
+		"BOT_RUN_MODE":             "webhook",
+		"WEBHOOK_URL":              "https://" + domain,
+		"WEB_API_ENABLED":          "true",
+		"INSTALLER_REVERSE_PROXY":  cfg.ReverseProxyType,
+		"INSTALLER_MINIAPP_DOMAIN": cfg.MiniappDomain,
+	}
+	if cfg.WebhookSecretToken == "" {
+		updates["WEBHOOK_SECRET_TOKEN"] = generateToken()
+	}
+	if err := updateEnvFile(envPath, updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	registerEnvSecrets(readEnvFile(envPath))
+	ui.PrintSuccess(".env обновлён: BOT_RUN_MODE=webhook, WEBHOOK_URL=https://" + domain)
+
+	if err := recreateBot(cfg.InstallDir, composeFile); err != nil {
+		ui.PrintError("Ошибка перезапуска: " + err.Error())
+		return
+	}
+	registerWebhook(cfg)
+	ui.PrintDim("Проверить доставку: bot webhook test")
+}
+
+func switchToPolling(cfg *Config, composeFile, token string) {
+	envPath := filepath.Join(cfg.InstallDir, ".env")
+	old := cfg.WebhookDomain
+	if cfg.BotRunMode == "polling" && old == "" {
+		ui.PrintInfo("Бот уже работает в режиме polling")
+		return
+	}
+
+	if err := deleteTelegramWebhook(token, false); err != nil {
+		ui.PrintWarning("Вебхук не снят: " + err.Error())
+	} else {
+		ui.PrintSuccess("Вебхук удалён в Telegram")
+	}
+
+	cfg.WebhookDomain = ""
+	if old != "" && old != cfg.MiniappDomain && cfg.ReverseProxyType != "" && cfg.ReverseProxyType != "skip" {
+		// polling не зависит от прокси: при ошибке сайт остаётся, переключение продолжается
+		if err := updateReverseProxy(cfg); err != nil {
+			ui.PrintWarning("Сайт " + old + " не удалён из прокси: " + err.Error())
+		} else {
+			offerCertificateRemoval(cfg, old)
+			ui.PrintSuccess("Сайт " + old + " удалён из прокси")
+		}
+	}
+
+	// web API остаётся включённым, если им пользуется Mini App
+	webAPI := "false"
+	if cfg.MiniappDomain != "" {
+		webAPI = "true"
+	}
+	updates := map[string]string{
+		"BOT_RUN_MODE":             "polling",
+		"WEBHOOK_URL":              "",
+		"WEB_API_ENABLED":          webAPI,
+		"INSTALLER_MINIAPP_DOMAIN": cfg.MiniappDomain,
+	}
+	if err := updateEnvFile(envPath, updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	ui.PrintSuccess(".env обновлён: BOT_RUN_MODE=polling")
+
+	if err := recreateBot(cfg.InstallDir, composeFile); err != nil {
+		ui.PrintError("Ошибка перезапуска: " + err.Error())
+		return
+	}
+	ui.PrintSuccess("Бот работает в режиме polling")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ════════════════════════════════════════════════════════════════
+// PACKAGE MANAGER
+// ════════════════════════════════════════════════════════════════
+
+// packageManager — команды пакетного менеджера дистрибутива.
+// Пакеты называются по-дебиановски, names переводит их в имена дистрибутива
+// ("" — пакета нет, пропускаем)
+type packageManager struct {
+	Name    string
+	update  string
+	install string
+	names   map[string]string
+}
+
+var (
+	aptManager = &packageManager{
+		Name:    "apt",
+		update:  "DEBIAN_FRONTEND=noninteractive apt-get update -y -qq",
+		install: "DEBIAN_FRONTEND=noninteractive apt-get install -y -qq",
+		names: map[string]string{
+			"certbot-nginx": "python3-certbot-nginx",
+		},
+	}
+	dnfManager = &packageManager{
+		Name:    "dnf",
+		update:  "dnf makecache -y -q",
+		install: "dnf install -y -q",
+		names:   rpmPackageNames,
+	}
+	yumManager = &packageManager{
+		Name:    "yum",
+		update:  "yum makecache -y -q",
+		install: "yum install -y -q",
+		names:   rpmPackageNames,
+	}
+	apkManager = &packageManager{
+		Name:    "apk",
+		update:  "apk update -q",
+		install: "apk add -q",
+		names: map[string]string{
+			"dnsutils":    "bind-tools",
+			"lsb-release": "",
+		},
+	}
+
+	rpmPackageNames = map[string]string{
+		"dnsutils":      "bind-utils",
+		"gnupg":         "gnupg2",
+		"lsb-release":   "",
+		"certbot-nginx": "python3-certbot-nginx",
+	}
+)
+
+// packageName переводит дебиановское имя пакета в имя дистрибутива
+func (pm *packageManager) packageName(pkg string) string {
+	if name, ok := pm.names[pkg]; ok {
+		return name
+	}
+	return pkg
+}
+
+func (pm *packageManager) installCmd(pkgs ...string) string {
+	var names []string
+	for _, p := range pkgs {
+		if name := pm.packageName(p); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "true"
+	}
+	return pm.install + " " + strings.Join(names, " ")
+}
+
+func (pm *packageManager) Update() {
+	runShellSilent(pm.update + " 2>/dev/null")
+}
+
+func (pm *packageManager) Install(pkgs ...string) error {
+	out, err := runShellSilent(pm.installCmd(pkgs...) + " 2>&1")
+	if err != nil {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	return nil
+}
+
+// rhelFamily — dnf/yum-дистрибутивы
+func (pm *packageManager) rhelFamily() bool {
+	return pm.Name == "dnf" || pm.Name == "yum"
+}
+
+var systemPM *packageManager
+
+// pkgManager определяет пакетный менеджер хоста (apt по умолчанию)
+func pkgManager() *packageManager {
+	if systemPM != nil {
+		return systemPM
+	}
+	switch {
+	case commandExists("apt-get"):
+		systemPM = aptManager
+	case commandExists("dnf"):
+		systemPM = dnfManager
+	case commandExists("yum"):
+		systemPM = yumManager
+	case commandExists("apk"):
+		systemPM = apkManager
+	default:
+		systemPM = aptManager
+	}
+	return systemPM
+}
+
+// serviceActive — служба запущена (systemd или OpenRC)
+func serviceActive(name string) bool {
+	if commandExists("systemctl") {
+		out, _ := runShellSilent("systemctl is-active " + name + " 2>/dev/null")
+		return out == "active"
+	}
+	_, err := runShellSilent("rc-service " + name + " status >/dev/null 2>&1")
+	return err == nil
+}
+
+// serviceCmd — systemctl или OpenRC (Alpine)
+func serviceCmd(action, name string) string {
+	if commandExists("systemctl") {
+		return fmt.Sprintf("systemctl %s %s", action, name)
+	}
+	switch action {
+	case "enable":
+		return fmt.Sprintf("rc-update add %s default", name)
+	case "disable":
+		return fmt.Sprintf("rc-update del %s default", name)
+	}
+	return fmt.Sprintf("rc-service %s %s", name, action)
+}
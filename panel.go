@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// REMNAWAVE PANEL API CHECK
+// ════════════════════════════════════════════════════════════════
+
+const (
+	panelCheckTimeout = 10 * time.Second
+	// Защищённый метод: отвечает только с действующим API-ключом
+	panelAuthPath     = "/api/system/stats"
+	panelMetadataPath = "/api/system/metadata"
+)
+
+// panelSecretCookie — cookie для защиты панели по секретному ключу (eGames):
+// REMNAWAVE_SECRET_KEY в формате имя:значение, иначе имя и значение совпадают
+func panelSecretCookie(secret string) *http.Cookie {
+	name, value, ok := strings.Cut(secret, ":")
+	if !ok {
+		value = name
+	}
+	return &http.Cookie{Name: name, Value: value}
+}
+
+// applyPanelAuth добавляет заголовки авторизации так же, как это делает бот
+func applyPanelAuth(req *http.Request, cfg *Config) {
+	req.Header.Set("X-Api-Key", cfg.RemnawaveAPIKey)
+	if cfg.RemnawaveAuthType == "basic_auth" && cfg.RemnawaveUsername != "" {
+		cred := base64.StdEncoding.EncodeToString([]byte(cfg.RemnawaveUsername + ":" + cfg.RemnawavePassword))
+		req.Header.Set("Authorization", "Basic "+cred)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+cfg.RemnawaveAPIKey)
+	}
+	if cfg.RemnawaveSecretKey != "" {
+		req.AddCookie(panelSecretCookie(cfg.RemnawaveSecretKey))
+	}
+	// Панель по HTTP принимает запросы только «из-за прокси»
+	if req.URL.Scheme == "http" {
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-For", "127.0.0.1")
+	}
+}
+
+// panelContainerIP — адрес контейнера панели в Docker-сети бота
+// (имя хоста из REMNAWAVE_API_URL — имя контейнера, например remnawave)
+func panelContainerIP(host, network string) (string, error) {
+	out, err := runShellSilent(fmt.Sprintf(`docker inspect -f '{{with index .NetworkSettings.Networks "%s"}}{{.IPAddress}}{{end}}' %s 2>/dev/null`, network, host))
+	if err != nil || net.ParseIP(out) == nil {
+		return "", fmt.Errorf("контейнер %s не найден в сети %s", host, network)
+	}
+	return out, nil
+}
+
+// panelClient — HTTP-клиент к панели. Для локальной панели имя контейнера
+// не резолвится с хоста: соединение идёт на его IP в Docker-сети, Host сохраняется
+func panelClient(cfg *Config, u *url.URL) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.PanelInstalledLocally && cfg.DockerNetwork != "" && net.ParseIP(u.Hostname()) == nil {
+		if _, err := net.LookupHost(u.Hostname()); err != nil {
+			ip, err := panelContainerIP(u.Hostname(), cfg.DockerNetwork)
+			if err != nil {
+				return nil, err
+			}
+			// локальная панель — прокси из окружения не используется
+			transport.Proxy = nil
+			dialer := &net.Dialer{Timeout: panelCheckTimeout}
+			transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				_, port, _ := net.SplitHostPort(addr)
+				return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			}
+		}
+	}
+	return &http.Client{
+		Timeout:   panelCheckTimeout,
+		Transport: transport,
+		// редирект на страницу входа — признак защиты, а не успех
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}, nil
+}
+
+// panelFailureReason объясняет код ответа панели
+func panelFailureReason(cfg *Config, status int, header http.Header) string {
+	switch {
+	case status == http.StatusUnauthorized && strings.HasPrefix(header.Get("WWW-Authenticate"), "Basic"):
+		if cfg.RemnawaveAuthType == "basic_auth" {
+			return "неверный логин или пароль Basic Auth"
+		}
+		return "панель закрыта Basic Auth — выберите тип авторизации Basic Auth"
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return "API-ключ отклонён панелью"
+	case status >= 300 && status < 400, status == http.StatusNotFound:
+		if cfg.RemnawaveSecretKey != "" {
+			return "неверный секретный ключ или URL панели"
+		}
+		return "неверный URL панели или панель защищена секретным ключом (REMNAWAVE_SECRET_KEY)"
+	case status >= 500:
+		return fmt.Sprintf("ошибка панели (HTTP %d)", status)
+	}
+	return fmt.Sprintf("неожиданный ответ (HTTP %d)", status)
+}
+
+// testPanelConnection выполняет авторизованный запрос к API панели.
+// Возвращает версию панели ("" — не удалось определить)
+func testPanelConnection(cfg *Config) (string, error) {
+	base, err := url.Parse(strings.TrimRight(cfg.RemnawaveAPIURL, "/"))
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return "", fmt.Errorf("неверный REMNAWAVE_API_URL: %s", cfg.RemnawaveAPIURL)
+	}
+	client, err := panelClient(cfg, base)
+	if err != nil {
+		return "", err
+	}
+	get := func(path string) (*http.Response, []byte, error) {
+		req, _ := http.NewRequest("GET", base.String()+path, nil)
+		applyPanelAuth(req, cfg)
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		return resp, body, nil
+	}
+
+	resp, body, err := get(panelAuthPath)
+	if err != nil {
+		return "", fmt.Errorf("панель недоступна: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s", panelFailureReason(cfg, resp.StatusCode, resp.Header))
+	}
+	if !json.Valid(body) {
+		// 200 с HTML — страница-заглушка защиты или чужой сайт
+		return "", fmt.Errorf("%s", panelFailureReason(cfg, http.StatusNotFound, resp.Header))
+	}
+
+	var meta struct {
+		Response struct {
+			Version string `json:"version"`
+		} `json:"response"`
+	}
+	if resp, body, err := get(panelMetadataPath); err == nil && resp.StatusCode == http.StatusOK {
+		json.Unmarshal(body, &meta)
+	}
+	return meta.Response.Version, nil
+}
+
+func describePanelVersion(version string) string {
+	if version == "" {
+		return "версия не определена"
+	}
+	return "версия " + version
+}
+
+// checkPanelAPI — проверка панели с выводом результата (мастер, bot health)
+func checkPanelAPI(cfg *Config) bool {
+	var version string
+	err := ui.RunWithSpinner("Проверка API панели Remnawave...", func() error {
+		var err error
+		version, err = testPanelConnection(cfg)
+		return err
+	})
+	if err != nil {
+		ui.PrintError("Панель Remnawave: " + err.Error())
+		return false
+	}
+	ui.PrintSuccess("Панель Remnawave: авторизация успешна, " + describePanelVersion(version))
+	return true
+}
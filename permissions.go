@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// PERMISSIONS
+// ════════════════════════════════════════════════════════════════
+
+// Режимы каталогов бота: logs/ и data/ принадлежат пользователю контейнера,
+// бэкапы (дампы БД и копии .env) и .env — только root
+const (
+	botDirMode    os.FileMode = 0750
+	botFileMode   os.FileMode = 0640
+	secretDirMode os.FileMode = 0700
+	secretMode    os.FileMode = 0600
+)
+
+// parseIDPair разбирает вывод "id -u; id -g"
+func parseIDPair(out string) (int, int, error) {
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("неожиданный вывод id: %q", out)
+	}
+	uid, err1 := strconv.Atoi(fields[0])
+	gid, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("неожиданный вывод id: %q", out)
+	}
+	return uid, gid, nil
+}
+
+// botContainerUser — UID/GID, под которыми работает контейнер бота.
+// Если контейнер перезапускается, id берётся из его образа
+func botContainerUser() (int, int, error) {
+	if out, err := runShellSilent("docker exec remnawave_bot sh -c 'id -u; id -g' 2>/dev/null"); err == nil {
+		return parseIDPair(out)
+	}
+	image, _ := runShellSilent("docker inspect --format '{{.Image}}' remnawave_bot 2>/dev/null")
+	if image == "" {
+		return 0, 0, fmt.Errorf("контейнер remnawave_bot не найден")
+	}
+	out, err := runShellSilent(fmt.Sprintf("docker run --rm --entrypoint sh %s -c 'id -u; id -g' 2>/dev/null", image))
+	if err != nil {
+		return 0, 0, fmt.Errorf("не удалось определить пользователя образа")
+	}
+	return parseIDPair(out)
+}
+
+func fileOwner(info os.FileInfo) (int, int) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(st.Uid), int(st.Gid)
+	}
+	return -1, -1
+}
+
+// fixPath приводит владельца и режим к нужным; uid<0 — владельца не трогать.
+// Возвращает описание изменения или ""
+func fixPath(path string, info os.FileInfo, uid, gid int, mode os.FileMode, dryRun bool) string {
+	var changes []string
+	if info.Mode().Perm() != mode {
+		changes = append(changes, fmt.Sprintf("%04o → %04o", info.Mode().Perm(), mode))
+		if !dryRun {
+			os.Chmod(path, mode)
+		}
+	}
+	if uid >= 0 {
+		if ou, og := fileOwner(info); ou != uid || og != gid {
+			changes = append(changes, fmt.Sprintf("%d:%d → %d:%d", ou, og, uid, gid))
+			if !dryRun {
+				os.Lchown(path, uid, gid)
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+	return path + ": " + strings.Join(changes, ", ")
+}
+
+// applyPermissions выставляет права на logs/, data/, бэкапы и .env.
+// dryRun — только перечислить расхождения
+func applyPermissions(installDir string, uid, gid int, dryRun bool) []string {
+	var changes []string
+	add := func(s string) {
+		if s != "" {
+			changes = append(changes, s)
+		}
+	}
+	backups := filepath.Join(installDir, "data", "backups")
+	// бэкапы пишет установщик (bot backup), он работает от root
+	rootUID, rootGID := os.Geteuid(), os.Getegid()
+
+	for _, root := range []string{filepath.Join(installDir, "logs"), filepath.Join(installDir, "data")} {
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.Mode()&os.ModeSymlink != 0 {
+				return nil
+			}
+			switch {
+			case path == backups || strings.HasPrefix(path, backups+string(os.PathSeparator)):
+				mode := secretMode
+				if info.IsDir() {
+					mode = secretDirMode
+				}
+				add(fixPath(path, info, rootUID, rootGID, mode, dryRun))
+			case info.IsDir():
+				add(fixPath(path, info, uid, gid, botDirMode, dryRun))
+			default:
+				add(fixPath(path, info, uid, gid, botFileMode, dryRun))
+			}
+			return nil
+		})
+	}
+
+	envFiles, _ := filepath.Glob(filepath.Join(installDir, ".env.backup_*"))
+	envFiles = append([]string{filepath.Join(installDir, ".env")}, envFiles...)
+	for _, f := range envFiles {
+		if info, err := os.Lstat(f); err == nil && info.Mode().IsRegular() {
+			add(fixPath(f, info, -1, -1, secretMode, dryRun))
+		}
+	}
+	return changes
+}
+
+// ensureBotPermissions — после первого запуска: права под пользователя контейнера,
+// перезапуск бота, если что-то поменялось
+func ensureBotPermissions(installDir, composeFile string) {
+	uid, gid, err := botContainerUser()
+	if err != nil {
+		ui.PrintWarning("Права не настроены: " + err.Error() + " (bot doctor --fix-permissions)")
+		return
+	}
+	if changes := applyPermissions(installDir, uid, gid, false); len(changes) > 0 {
+		runShellSilent(composeCmd(installDir, composeFile) + " restart bot 2>/dev/null")
+	}
+	ui.PrintSuccess(fmt.Sprintf("Права каталогов: владелец %d:%d, logs/data %04o, бэкапы и .env %04o", uid, gid, botDirMode, secretMode))
+}
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: DOCTOR
+// ════════════════════════════════════════════════════════════════
+
+func manageDoctor(installDir, composeFile string, args []string) {
+	fix := containsString(args, "--fix-permissions")
+
+	fmt.Println()
+	fmt.Println(ui.AccentBar.Render("  ПРОВЕРКА ПРАВ"))
+	fmt.Println()
+
+	uid, gid, err := botContainerUser()
+	if err != nil {
+		ui.PrintError(err.Error())
+		ui.PrintDim("Запустите бота (bot start) и повторите")
+		return
+	}
+	ui.PrintInfo(fmt.Sprintf("Контейнер бота работает как %d:%d", uid, gid))
+
+	changes := applyPermissions(installDir, uid, gid, !fix)
+	if len(changes) == 0 {
+		ui.PrintSuccess("Права в порядке")
+		return
+	}
+	const maxShown = 20
+	for i, c := range changes {
+		if i == maxShown {
+			ui.PrintDim(fmt.Sprintf("... и ещё %d", len(changes)-maxShown))
+			break
+		}
+		rel := strings.TrimPrefix(c, installDir+"/")
+		ui.PrintDim(rel)
+	}
+	if !fix {
+		ui.PrintWarning(fmt.Sprintf("Найдено расхождений: %d. Исправить: bot doctor --fix-permissions", len(changes)))
+		return
+	}
+	runShellSilent(composeCmd(installDir, composeFile) + " restart bot 2>/dev/null")
+	ui.PrintSuccess(fmt.Sprintf("Исправлено: %d, бот перезапущен", len(changes)))
+}
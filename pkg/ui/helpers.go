@@ -1,32 +1,42 @@
 package ui
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // ════════════════════════════════════════════════════════════════
 // PRINT HELPERS
 // ════════════════════════════════════════════════════════════════
 
-func PrintStep(msg string)    { fmt.Println("\n" + StepStyle.Render("  ▸ "+msg)) }
-func PrintInfo(msg string)    { fmt.Println(InfoStyle.Render("  ℹ " + msg)) }
-func PrintSuccess(msg string) { fmt.Println(SuccessStyle.Render("  ✓ " + msg)) }
-func PrintError(msg string)   { fmt.Println(ErrorStyle.Render("  ✗ " + msg)) }
-func PrintWarning(msg string) { fmt.Println(WarnStyle.Render("  ⚠ " + msg)) }
-func PrintDim(msg string)     { fmt.Println(DimStyle.Render("    " + msg)) }
+// Все сообщения проходят через Redact — секреты не попадают на экран
+
+func PrintStep(msg string)    { fmt.Println("\n" + StepStyle.Render("  ▸ "+Redact(msg))) }
+func PrintInfo(msg string)    { fmt.Println(InfoStyle.Render("  ℹ " + Redact(msg))) }
+func PrintSuccess(msg string) { fmt.Println(SuccessStyle.Render("  ✓ " + Redact(msg))) }
+func PrintError(msg string)   { fmt.Println(ErrorStyle.Render("  ✗ " + Redact(msg))) }
+func PrintWarning(msg string) { fmt.Println(WarnStyle.Render("  ⚠ " + Redact(msg))) }
+func PrintDim(msg string)     { fmt.Println(DimStyle.Render("    " + Redact(msg))) }
 
-func PrintLiveInfo(msg string)    { fmt.Printf("\r\033[K%s\n", InfoStyle.Render("  ℹ "+msg)) }
-func PrintLiveSuccess(msg string) { fmt.Printf("\r\033[K%s\n", SuccessStyle.Render("  ✓ "+msg)) }
-func PrintLiveWarning(msg string) { fmt.Printf("\r\033[K%s\n", WarnStyle.Render("  ⚠ "+msg)) }
-func PrintLiveError(msg string)   { fmt.Printf("\r\033[K%s\n", ErrorStyle.Render("  ✗ "+msg)) }
+func PrintLiveInfo(msg string)    { printLive(InfoStyle, "  ℹ "+msg) }
+func PrintLiveSuccess(msg string) { printLive(SuccessStyle, "  ✓ "+msg) }
+func PrintLiveWarning(msg string) { printLive(WarnStyle, "  ⚠ "+msg) }
+func PrintLiveError(msg string)   { printLive(ErrorStyle, "  ✗ "+msg) }
+
+func printLive(style lipgloss.Style, msg string) {
+	fmt.Printf("\r\033[K%s\n", style.Render(Redact(msg)))
+}
 
 func PrintBox(title, content string) {
-	inner := SubtitleStyle.Render(title) + "\n" + content
+	inner := SubtitleStyle.Render(title) + "\n" + Redact(content)
 	fmt.Println(BoxStyle.Render(inner))
 }
 
 func PrintSuccessBox(content string) {
-	fmt.Println(SuccessBoxStyle.Render(content))
+	fmt.Println(SuccessBoxStyle.Render(Redact(content)))
 }
 
 func PrintErrorBox(content string) {
-	fmt.Println(ErrorBoxStyle.Render(content))
+	fmt.Println(ErrorBoxStyle.Render(Redact(content)))
 }
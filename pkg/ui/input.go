@@ -25,9 +25,11 @@ type inputModel struct {
 	required bool
 	errMsg   string
 	ready    bool
+	secret   bool
+	revealed bool
 }
 
-func newInputModel(label, placeholder, hint string, required bool) inputModel {
+func newInputModel(label, placeholder, hint string, required, secret bool) inputModel {
 	ti := textinput.New()
 	ti.Placeholder = placeholder
 	ti.Focus()
@@ -37,12 +39,17 @@ func newInputModel(label, placeholder, hint string, required bool) inputModel {
 	ti.TextStyle = lipgloss.NewStyle().Foreground(ColorWhite)
 	ti.PlaceholderStyle = DimStyle
 	ti.Cursor.Style = lipgloss.NewStyle().Foreground(ColorAccent)
+	if secret {
+		ti.EchoMode = textinput.EchoPassword
+		ti.EchoCharacter = '•'
+	}
 
 	return inputModel{
 		input:    ti,
 		label:    label,
 		hint:     hint,
 		required: required,
+		secret:   secret,
 	}
 }
 
@@ -75,6 +82,16 @@ func (m inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "ctrl+c":
 			return m, nil
+		case "ctrl+r":
+			// показать/скрыть вводимый секрет
+			if m.secret {
+				m.revealed = !m.revealed
+				m.input.EchoMode = textinput.EchoPassword
+				if m.revealed {
+					m.input.EchoMode = textinput.EchoNormal
+				}
+				return m, nil
+			}
 		case "esc":
 			if !m.required {
 				m.done = true
@@ -103,19 +120,39 @@ func (m inputModel) View() string {
 	if m.errMsg != "" {
 		b.WriteString("  " + ErrorStyle.Render("✗ "+m.errMsg) + "\n")
 	}
-	if m.required {
-		b.WriteString("\n  " + DimStyle.Render("Enter Подтвердить") + "\n")
-	} else {
-		b.WriteString("\n  " + DimStyle.Render("Enter Подтвердить  Esc Пропустить") + "\n")
+	keys := "Enter Подтвердить"
+	if !m.required {
+		keys += "  Esc Пропустить"
+	}
+	if m.secret {
+		if m.revealed {
+			keys += "  Ctrl+R Скрыть"
+		} else {
+			keys += "  Ctrl+R Показать"
+		}
 	}
+	b.WriteString("\n  " + DimStyle.Render(keys) + "\n")
 	return b.String()
 }
 
+// InputText — обычное поле ввода, значение выводится после подтверждения
 func InputText(label, placeholder, hint string, required bool) string {
+	return runInput(label, placeholder, hint, required, false)
+}
+
+// InputSecret — ввод пароля или токена: символы скрыты (Ctrl+R — показать),
+// значение регистрируется для Redact и выводится только в сокращённом виде
+func InputSecret(label, placeholder, hint string, required bool) string {
+	val := runInput(label, placeholder, hint, required, true)
+	RegisterSecret(val)
+	return val
+}
+
+func runInput(label, placeholder, hint string, required, secret bool) string {
 	maxRetries := 3
 	retries := 0
 	for {
-		m := newInputModel(label, placeholder, hint, required)
+		m := newInputModel(label, placeholder, hint, required, secret)
 		p := tea.NewProgram(m)
 		result, err := p.Run()
 		if err != nil {
@@ -138,15 +175,10 @@ func InputText(label, placeholder, hint string, required bool) string {
 		}
 		if val != "" {
 			displayVal := val
-			labelLower := strings.ToLower(label)
-			if strings.Contains(labelLower, "token") || strings.Contains(labelLower, "key") || strings.Contains(labelLower, "password") || strings.Contains(labelLower, "secret") {
-				if len(val) > 8 {
-					displayVal = val[:4] + "..." + val[len(val)-4:]
-				} else {
-					displayVal = "***"
-				}
+			if secret {
+				displayVal = MaskSecret(val)
 			}
-			fmt.Println(SuccessStyle.Render(fmt.Sprintf("  ✓ %s: %s", label, displayVal)))
+			fmt.Println(SuccessStyle.Render(fmt.Sprintf("  ✓ %s: %s", label, Redact(displayVal))))
 		} else {
 			fmt.Println(DimStyle.Render(fmt.Sprintf("  - %s: пропущено", label)))
 		}
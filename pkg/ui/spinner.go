@@ -25,7 +25,7 @@ func newSpinnerModel(msg string) spinnerModel {
 	s := spinner.New()
 	s.Spinner = spinner.MiniDot
 	s.Style = lipgloss.NewStyle().Foreground(ColorAccent)
-	return spinnerModel{spinner: s, message: msg}
+	return spinnerModel{spinner: s, message: Redact(msg)}
 }
 
 func (m spinnerModel) Init() tea.Cmd { return m.spinner.Tick }
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// PORTS
+// ════════════════════════════════════════════════════════════════
+
+const defaultWebAPIPort = "8080"
+
+func webAPIPort(cfg *Config) string {
+	if cfg.WebAPIPort == "" {
+		return defaultWebAPIPort
+	}
+	return cfg.WebAPIPort
+}
+
+// webAPIUpstream — адрес бота для nginx/Caddy на хосте: порт опубликован
+// только на WebAPIBind, при публикации на всех интерфейсах — 127.0.0.1
+func webAPIUpstream(cfg *Config) string {
+	host := cfg.WebAPIBind
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, webAPIPort(cfg))
+}
+
+// composeBindAddress — адрес публикации в формате ports: compose (IPv6 в скобках)
+func composeBindAddress(bind string) string {
+	if ip := net.ParseIP(bind); ip != nil && ip.To4() == nil {
+		return "[" + bind + "]"
+	}
+	return bind
+}
+
+// webAPIExposureFix — как закрыть публичный порт web API. Compose-файл установки
+// без настроек установщика в .env (до INSTALLER_REVERSE_PROXY) bot update не
+// пересоздаёт — для неё указывается строка, которую нужно исправить вручную
+func webAPIExposureFix(cfg *Config) string {
+	if cfg.ReverseProxyType != "" {
+		return "установите INSTALLER_WEB_API_BIND=127.0.0.1 в .env и выполните bot update"
+	}
+	port := webAPIPort(cfg)
+	return fmt.Sprintf(`в %s замените "%s:%s" на "127.0.0.1:%s:%s" и выполните bot start`,
+		filepath.Join(cfg.InstallDir, detectComposeFile(cfg.InstallDir)), port, port, port, port)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n < 65536
+}
+
+// portInUse проверяет, слушает ли кто-то TCP-порт на хосте
+func portInUse(port string) bool {
+	ln, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return true
+	}
+	ln.Close()
+	return false
+}
+
+// portOwner возвращает имя процесса, слушающего порт (по данным ss)
+func portOwner(port string) string {
+	out, _ := runShellSilent(fmt.Sprintf(`ss -ltnpH "sport = :%s" 2>/dev/null | grep -o 'users:(("[^"]*"' | head -1 | cut -d'"' -f2`, port))
+	return out
+}
+
+// portUsedByBot — порт уже опубликован контейнером бота (переустановка)
+func portUsedByBot(port string) bool {
+	out, _ := runShellSilent("docker port remnawave_bot 2>/dev/null")
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), ":"+port) {
+			return true
+		}
+	}
+	return false
+}
+
+// findFreePort ищет первый свободный порт начиная со start
+func findFreePort(start string) string {
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		n, _ = strconv.Atoi(defaultWebAPIPort)
+	}
+	for p := n; p < n+100 && p < 65536; p++ {
+		port := strconv.Itoa(p)
+		if !portInUse(port) {
+			return port
+		}
+	}
+	return ""
+}
+
+func describePortOwner(port string) string {
+	if owner := portOwner(port); owner != "" {
+		return fmt.Sprintf("порт %s занят (%s)", port, owner)
+	}
+	return fmt.Sprintf("порт %s занят", port)
+}
+
+// checkProxyPorts предупреждает о занятых 80/443 для выбранного обратного прокси
+func checkProxyPorts(cfg *Config) {
+	var expected []string
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		expected = []string{"nginx"}
+	case "caddy":
+		// установщик сам останавливает nginx/apache/remnawave-nginx
+		expected = []string{"nginx", "apache2", "httpd", "caddy", "docker-proxy"}
+	default:
+		return
+	}
+	for _, port := range []string{"80", "443"} {
+		if !portInUse(port) {
+			continue
+		}
+		owner := portOwner(port)
+		known := false
+		for _, e := range expected {
+			if owner == e {
+				known = true
+			}
+		}
+		if !known {
+			ui.PrintWarning(describePortOwner(port) + " — обратный прокси может не запуститься")
+		}
+	}
+}
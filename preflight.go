@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// PREFLIGHT
+// ════════════════════════════════════════════════════════════════
+
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// preflightCheck — одна строка таблицы preflight
+type preflightCheck struct {
+	Name   string
+	Value  string
+	Status checkStatus
+	Hint   string
+}
+
+const (
+	minMemoryMB      = 512
+	recommendedMemMB = 1024
+	minDiskMB        = 2048
+	recommendedDisk  = 5120
+)
+
+// checkArch — uname -m; образы postgres/redis/бота собираются под amd64 и arm64
+func checkArch(machine string) preflightCheck {
+	c := preflightCheck{Name: "Архитектура", Value: machine}
+	switch machine {
+	case "x86_64", "amd64", "aarch64", "arm64":
+	default:
+		c.Status, c.Hint = checkFail, "поддерживаются только x86_64 и aarch64"
+	}
+	return c
+}
+
+// parseVersion разбирает "5.15.0-91-generic" / "24.0.7" в major, minor
+func parseVersion(v string) (int, int, bool) {
+	parts := strings.SplitN(v, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(parts[1][:countDigits(parts[1])])
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+func countDigits(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+func versionLess(major, minor, wantMajor, wantMinor int) bool {
+	return major < wantMajor || (major == wantMajor && minor < wantMinor)
+}
+
+func checkKernel(release string) preflightCheck {
+	c := preflightCheck{Name: "Ядро", Value: release}
+	major, minor, ok := parseVersion(release)
+	switch {
+	case !ok:
+		c.Status, c.Hint = checkWarn, "не удалось определить версию"
+	case versionLess(major, minor, 3, 10):
+		c.Status, c.Hint = checkFail, "Docker требует ядро 3.10+"
+	case versionLess(major, minor, 4, 15):
+		c.Status, c.Hint = checkWarn, "рекомендуется ядро 4.15+"
+	}
+	return c
+}
+
+func checkMemory(totalMB, availableMB int) preflightCheck {
+	c := preflightCheck{Name: "Память", Value: fmt.Sprintf("%d МБ, свободно %d МБ", totalMB, availableMB)}
+	switch {
+	case totalMB == 0:
+		c.Status, c.Hint = checkWarn, "не удалось прочитать /proc/meminfo"
+	case totalMB < minMemoryMB:
+		c.Status, c.Hint = checkFail, fmt.Sprintf("минимум %d МБ", minMemoryMB)
+	case totalMB < recommendedMemMB || availableMB < minMemoryMB/2:
+		c.Status, c.Hint = checkWarn, "мало памяти — используйте профиль small и готовый образ"
+	}
+	return c
+}
+
+func checkSwap(totalMB, swapMB int) preflightCheck {
+	c := preflightCheck{Name: "Swap", Value: fmt.Sprintf("%d МБ", swapMB)}
+	if swapMB == 0 && totalMB < 2048 {
+		c.Status, c.Hint = checkWarn, "без swap сборка образа может упасть по OOM"
+	}
+	return c
+}
+
+func checkDisk(path string, freeMB int) preflightCheck {
+	c := preflightCheck{Name: "Диск " + path, Value: fmt.Sprintf("свободно %d МБ", freeMB)}
+	switch {
+	case freeMB < 0:
+		c.Value, c.Status, c.Hint = "?", checkWarn, "не удалось определить свободное место"
+	case freeMB < minDiskMB:
+		c.Status, c.Hint = checkFail, fmt.Sprintf("минимум %d МБ", minDiskMB)
+	case freeMB < recommendedDisk:
+		c.Status, c.Hint = checkWarn, fmt.Sprintf("рекомендуется %d МБ (образы, бэкапы, логи)", recommendedDisk)
+	}
+	return c
+}
+
+// checkDockerVersion — пустая версия значит, что Docker будет установлен
+func checkDockerVersion(version string) preflightCheck {
+	c := preflightCheck{Name: "Docker", Value: version}
+	if version == "" {
+		c.Value = "не установлен (будет установлен)"
+		return c
+	}
+	major, minor, ok := parseVersion(version)
+	switch {
+	case !ok:
+		c.Status, c.Hint = checkWarn, "не удалось определить версию"
+	case versionLess(major, minor, 20, 10):
+		c.Status, c.Hint = checkFail, "нужен Docker 20.10+ (compose v2, host-gateway)"
+	case versionLess(major, minor, 23, 0):
+		c.Status, c.Hint = checkWarn, "рекомендуется Docker 23+"
+	}
+	return c
+}
+
+func diskFreeMB(path string) int {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return -1
+	}
+	return int(st.Bavail * uint64(st.Bsize) / 1024 / 1024)
+}
+
+func checkPort(port string) preflightCheck {
+	c := preflightCheck{Name: "Порт " + port, Value: "свободен"}
+	if !portInUse(port) {
+		return c
+	}
+	c.Value = "занят"
+	if owner := portOwner(port); owner != "" {
+		c.Value += " (" + owner + ")"
+	}
+	c.Status = checkWarn
+	if port == defaultWebAPIPort {
+		if portUsedByBot(port) {
+			c.Status, c.Value = checkPass, "занят ботом (переустановка)"
+		} else {
+			c.Hint = "порт web API можно будет сменить"
+		}
+	} else {
+		c.Hint = "учтите при выборе обратного прокси"
+	}
+	return c
+}
+
+func checkWebServers() preflightCheck {
+	c := preflightCheck{Name: "Веб-серверы", Value: "нет"}
+	var found []string
+	for _, name := range []string{"nginx", "apache2", "httpd", "caddy"} {
+		if serviceActive(name) {
+			found = append(found, name+" (запущен)")
+		} else if commandExists(name) {
+			found = append(found, name)
+		}
+	}
+	if len(found) > 0 {
+		c.Value, c.Status, c.Hint = strings.Join(found, ", "), checkWarn, "Caddy-режим остановит nginx/apache"
+	}
+	return c
+}
+
+func checkSystemd() preflightCheck {
+	c := preflightCheck{Name: "systemd", Value: "есть"}
+	if !dirExists("/run/systemd/system") {
+		c.Value, c.Status, c.Hint = "нет", checkWarn, "службы управляются через OpenRC, продление сертификатов — через crond"
+	}
+	return c
+}
+
+func checkCgroup() preflightCheck {
+	c := preflightCheck{Name: "cgroup", Value: "v2"}
+	if !fileExists("/sys/fs/cgroup/cgroup.controllers") {
+		c.Value, c.Status, c.Hint = "v1", checkWarn, "лимиты ресурсов могут работать частично"
+	}
+	return c
+}
+
+func checkTimeSync() preflightCheck {
+	c := preflightCheck{Name: "Синхронизация времени", Value: "да"}
+	out, err := runShellSilent("timedatectl show -p NTPSynchronized --value 2>/dev/null")
+	switch {
+	case err != nil || out == "":
+		c.Value, c.Status, c.Hint = "?", checkWarn, "timedatectl недоступен"
+	case out != "yes":
+		c.Value, c.Status, c.Hint = "нет", checkWarn, "Telegram и SSL чувствительны к времени: timedatectl set-ntp true"
+	}
+	return c
+}
+
+// runPreflight собирает все проверки хоста
+func runPreflight() []preflightCheck {
+	meminfo, _ := os.ReadFile("/proc/meminfo")
+	totalMB := parseMeminfoMB(string(meminfo), "MemTotal")
+	kernel, _ := runShellSilent("uname -r")
+	machine, _ := runShellSilent("uname -m")
+	if machine == "" {
+		machine = runtime.GOARCH
+	}
+	dockerVer := ""
+	if commandExists("docker") {
+		dockerVer, _ = runShellSilent("docker version --format '{{.Server.Version}}' 2>/dev/null")
+	}
+	diskPath := "/opt"
+	if !dirExists(diskPath) {
+		diskPath = "/"
+	}
+
+	checks := []preflightCheck{
+		checkArch(machine),
+		checkKernel(kernel),
+		checkMemory(totalMB, parseMeminfoMB(string(meminfo), "MemAvailable")),
+		checkSwap(totalMB, parseMeminfoMB(string(meminfo), "SwapTotal")),
+		checkDisk(diskPath, diskFreeMB(diskPath)),
+	}
+	for _, port := range []string{"80", "443", defaultWebAPIPort} {
+		checks = append(checks, checkPort(port))
+	}
+	return append(checks,
+		checkWebServers(),
+		checkDockerVersion(dockerVer),
+		checkSystemd(),
+		checkCgroup(),
+		checkTimeSync(),
+	)
+}
+
+func preflightFailed(checks []preflightCheck) bool {
+	for _, c := range checks {
+		if c.Status == checkFail {
+			return true
+		}
+	}
+	return false
+}
+
+func printPreflight(checks []preflightCheck) {
+	for _, c := range checks {
+		line := fmt.Sprintf("%-24s %s", c.Name, c.Value)
+		if c.Hint != "" {
+			line += " — " + c.Hint
+		}
+		switch c.Status {
+		case checkPass:
+			fmt.Println(ui.SuccessStyle.Render("  ✓ ") + line)
+		case checkWarn:
+			fmt.Println(ui.WarnStyle.Render("  ⚠ " + line))
+		case checkFail:
+			fmt.Println(ui.ErrorStyle.Render("  ✗ " + line))
+		}
+	}
+}
+
+// preflightStep — шаг 1 мастера: отказ от установки при критических проблемах
+func preflightStep() {
+	checks := runPreflight()
+	printPreflight(checks)
+	if preflightFailed(checks) {
+		globalProgress.fail("Сервер не соответствует минимальным требованиям")
+		os.Exit(1)
+	}
+	offerSwap()
+	globalProgress.done("Требования к системе выполнены")
+}
+
+// preflightCommand — bedolaga_installer preflight
+func preflightCommand() {
+	ui.PrintBanner(appVersion)
+	ui.PrintStep("Проверка системы")
+	checks := runPreflight()
+	printPreflight(checks)
+	fmt.Println()
+	if preflightFailed(checks) {
+		ui.PrintError("Сервер не соответствует минимальным требованиям")
+		os.Exit(1)
+	}
+	if os.Getuid() == 0 {
+		offerSwap()
+	}
+	ui.PrintSuccess("Можно устанавливать")
+}
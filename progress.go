@@ -71,29 +71,29 @@ func (p *installProgress) log(msg string) {
 	if p.silent {
 		return
 	}
-	fmt.Printf("\r\033[K%s\n", msg)
+	fmt.Printf("\r\033[K%s\n", ui.Redact(msg))
 	if p.lastLine != "" {
 		fmt.Printf("%s", p.lastLine)
 	}
 }
 
 func (p *installProgress) done(msg string) {
-	fmt.Printf("\r\033[K%s\n", ui.SuccessStyle.Render("  ✓ "+msg))
+	fmt.Printf("\r\033[K%s\n", ui.SuccessStyle.Render("  ✓ "+ui.Redact(msg)))
 	if p.lastLine != "" && p.current < p.total {
 		fmt.Printf("%s", p.lastLine)
 	}
 }
 
 func (p *installProgress) fail(msg string) {
-	fmt.Printf("\r\033[K%s\n", ui.ErrorStyle.Render("  ✗ "+msg))
+	fmt.Printf("\r\033[K%s\n", ui.ErrorStyle.Render("  ✗ "+ui.Redact(msg)))
 }
 
 func (p *installProgress) warn(msg string) {
-	fmt.Printf("\r\033[K%s\n", ui.WarnStyle.Render("  ⚠ "+msg))
+	fmt.Printf("\r\033[K%s\n", ui.WarnStyle.Render("  ⚠ "+ui.Redact(msg)))
 }
 
 func (p *installProgress) info(msg string) {
-	fmt.Printf("\r\033[K%s\n", ui.InfoStyle.Render("  ℹ "+msg))
+	fmt.Printf("\r\033[K%s\n", ui.InfoStyle.Render("  ℹ "+ui.Redact(msg)))
 }
 
 // ════════════════════════════════════════════════════════════════
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	"bedolaga-installer/pkg/ui"
@@ -13,13 +15,178 @@ import (
 // NGINX SETUP
 // ════════════════════════════════════════════════════════════════
 
+// applyReverseProxy создаёт (или пересоздаёт) сайты доменов бота для выбранного прокси
+func applyReverseProxy(cfg *Config) {
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		setupNginxSystem(cfg)
+	case "nginx_panel":
+		setupNginxPanel(cfg)
+	case "caddy":
+		setupCaddy(cfg)
+	}
+}
+
+// reloadReverseProxy применяет и проверяет новую конфигурацию: nginx перезагружается
+// в applyReverseProxy (здесь — проверка nginx -t), контейнер Caddy — здесь
+func reloadReverseProxy(cfg *Config) error {
+	var cmd string
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		cmd = "nginx -t 2>&1 && " + serviceCmd("reload", "nginx") + " 2>&1"
+	case "nginx_panel":
+		cmd = "docker exec remnawave-nginx nginx -t 2>&1"
+	case "caddy":
+		cmd = fmt.Sprintf("cd %s && docker compose -f docker-compose.caddy.yml up -d 2>&1 && docker exec remnawave_caddy caddy reload --config /etc/caddy/Caddyfile 2>&1", cfg.InstallDir)
+	default:
+		return nil
+	}
+	out, err := runShellSilent(cmd)
+	if err != nil {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	return nil
+}
+
+// proxyFile — состояние файла конфигурации прокси до изменения
+type proxyFile struct {
+	path   string
+	exists bool
+	link   string // цель симлинка (sites-enabled)
+	data   []byte
+}
+
+// proxyConfigFiles — файлы, которые переписывает applyReverseProxy
+func proxyConfigFiles(cfg *Config) []string {
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		var files []string
+		for _, site := range []string{"bedolaga-webhook", "bedolaga-miniapp"} {
+			files = append(files, "/etc/nginx/sites-available/"+site, "/etc/nginx/sites-enabled/"+site)
+		}
+		return files
+	case "nginx_panel":
+		return []string{filepath.Join(cfg.PanelDir, "nginx.conf")}
+	case "caddy":
+		return []string{filepath.Join(cfg.InstallDir, "caddy", "Caddyfile"), filepath.Join(cfg.InstallDir, "docker-compose.caddy.yml")}
+	}
+	return nil
+}
+
+func snapshotProxyConfig(cfg *Config) []proxyFile {
+	var snap []proxyFile
+	for _, path := range proxyConfigFiles(cfg) {
+		f := proxyFile{path: path}
+		if info, err := os.Lstat(path); err == nil {
+			f.exists = true
+			if info.Mode()&os.ModeSymlink != 0 {
+				f.link, _ = os.Readlink(path)
+			} else {
+				f.data, _ = os.ReadFile(path)
+			}
+		}
+		snap = append(snap, f)
+	}
+	return snap
+}
+
+// restoreProxyFiles возвращает файлы конфигурации прокси из снимка
+func restoreProxyFiles(snap []proxyFile) {
+	for _, f := range snap {
+		os.Remove(f.path)
+		switch {
+		case !f.exists:
+		case f.link != "":
+			os.Symlink(f.link, f.path)
+		default:
+			os.WriteFile(f.path, f.data, 0644)
+		}
+	}
+}
+
+// restoreProxyConfig возвращает прежнюю конфигурацию и перезапускает с ней прокси
+func restoreProxyConfig(cfg *Config, snap []proxyFile) {
+	restoreProxyFiles(snap)
+	if cfg.ReverseProxyType == "nginx_panel" {
+		runShellSilent("docker restart remnawave-nginx 2>/dev/null || true")
+		return
+	}
+	reloadReverseProxy(cfg)
+}
+
+// updateReverseProxy пересоздаёт сайты доменов бота (bot mode, bot domain) и получает
+// сертификаты для newDomains. Если сертификат не получен или прокси не принял
+// конфигурацию, прежняя восстанавливается — домен без TLS не попадёт в .env
+func updateReverseProxy(cfg *Config, newDomains ...string) error {
+	snap := snapshotProxyConfig(cfg)
+	applyReverseProxy(cfg)
+	err := refreshCertificates(cfg, newDomains...)
+	if err == nil {
+		err = reloadReverseProxy(cfg)
+	}
+	if err != nil {
+		restoreProxyConfig(cfg, snap)
+		return fmt.Errorf("%v — прежняя конфигурация прокси восстановлена", err)
+	}
+	return nil
+}
+
+var (
+	nginxServerNameRe = regexp.MustCompile(`server_name\s+([^\s;]+);`)
+	// в блоке панели сервер Mini App — тот, где есть location /miniapp/
+	panelMiniappRe = regexp.MustCompile(`server_name\s+([^\s;]+);[^}]*?location /miniapp/`)
+	caddyMiniappRe = regexp.MustCompile(`(?m)^(\S+) \{\s*\n\s*@api path /miniapp/\*`)
+)
+
+// parseMiniappDomain извлекает домен Mini App из конфигурации прокси
+// (установки, где INSTALLER_MINIAPP_DOMAIN ещё не сохранялся)
+func parseMiniappDomain(proxyType, conf string) string {
+	var m []string
+	switch proxyType {
+	case "nginx_system":
+		m = nginxServerNameRe.FindStringSubmatch(conf)
+	case "nginx_panel":
+		if _, block, ok := strings.Cut(conf, "# === BEGIN Bedolaga Bot ==="); ok {
+			m = panelMiniappRe.FindStringSubmatch(block)
+		}
+	case "caddy":
+		m = caddyMiniappRe.FindStringSubmatch(conf)
+	}
+	if len(m) < 2 {
+		return ""
+	}
+	return m[1]
+}
+
+// miniappDomainFromProxy — домен Mini App из текущей конфигурации прокси
+func miniappDomainFromProxy(cfg *Config) string {
+	var path string
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		path = "/etc/nginx/sites-available/bedolaga-miniapp"
+	case "nginx_panel":
+		path = filepath.Join(cfg.PanelDir, "nginx.conf")
+	case "caddy":
+		path = filepath.Join(cfg.InstallDir, "caddy", "Caddyfile")
+	default:
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return parseMiniappDomain(cfg.ReverseProxyType, string(data))
+}
+
 func setupNginxSystem(cfg *Config) {
 	installNginx()
 
+	upstream := webAPIUpstream(cfg)
 	nginxAvail := "/etc/nginx/sites-available"
 	nginxEnabled := "/etc/nginx/sites-enabled"
 	os.MkdirAll(nginxAvail, 0755)
 	os.MkdirAll(nginxEnabled, 0755)
+	ensureNginxSitesInclude(nginxEnabled)
 
 	if cfg.WebhookDomain != "" {
 		conf := fmt.Sprintf(`server {
@@ -28,7 +195,7 @@ func setupNginxSystem(cfg *Config) {
     client_max_body_size 32m;
 
     location / {
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
         proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;
@@ -41,15 +208,19 @@ func setupNginxSystem(cfg *Config) {
 
     location = /app-config.json {
         add_header Access-Control-Allow-Origin "*";
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
     }
 }
-`, cfg.WebhookDomain)
+`, cfg.WebhookDomain, upstream, upstream)
 		os.WriteFile(filepath.Join(nginxAvail, "bedolaga-webhook"), []byte(conf), 0644)
 		os.Remove(filepath.Join(nginxEnabled, "bedolaga-webhook"))
 		os.Symlink(filepath.Join(nginxAvail, "bedolaga-webhook"), filepath.Join(nginxEnabled, "bedolaga-webhook"))
+	} else {
+		// домен убран (bot mode polling, bot domain remove) — сайт больше не нужен
+		os.Remove(filepath.Join(nginxEnabled, "bedolaga-webhook"))
+		os.Remove(filepath.Join(nginxAvail, "bedolaga-webhook"))
 	}
 
 	if cfg.MiniappDomain != "" {
@@ -67,7 +238,7 @@ func setupNginxSystem(cfg *Config) {
     }
 
     location /miniapp/ {
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_http_version 1.1;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
@@ -79,21 +250,39 @@ func setupNginxSystem(cfg *Config) {
 
     location = /app-config.json {
         add_header Access-Control-Allow-Origin "*";
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
     }
 }
-`, cfg.MiniappDomain, cfg.InstallDir)
+`, cfg.MiniappDomain, cfg.InstallDir, upstream, upstream)
 		os.WriteFile(filepath.Join(nginxAvail, "bedolaga-miniapp"), []byte(conf), 0644)
 		os.Remove(filepath.Join(nginxEnabled, "bedolaga-miniapp"))
 		os.Symlink(filepath.Join(nginxAvail, "bedolaga-miniapp"), filepath.Join(nginxEnabled, "bedolaga-miniapp"))
+	} else {
+		os.Remove(filepath.Join(nginxEnabled, "bedolaga-miniapp"))
+		os.Remove(filepath.Join(nginxAvail, "bedolaga-miniapp"))
 	}
 
-	runShellSilent("nginx -t && systemctl reload nginx")
+	runShellSilent("nginx -t && " + serviceCmd("reload", "nginx"))
 	ui.PrintSuccess("Nginx настроен")
 }
 
+// ensureNginxSitesInclude подключает sites-enabled там, где его нет в nginx.conf
+// (RHEL, Alpine используют только conf.d / http.d)
+func ensureNginxSitesInclude(enabledDir string) {
+	conf, err := os.ReadFile("/etc/nginx/nginx.conf")
+	if err != nil || strings.Contains(string(conf), "sites-enabled") {
+		return
+	}
+	for _, dir := range []string{"/etc/nginx/conf.d", "/etc/nginx/http.d"} {
+		if dirExists(dir) {
+			os.WriteFile(filepath.Join(dir, "bedolaga-sites.conf"), []byte("include "+enabledDir+"/*;\n"), 0644)
+			return
+		}
+	}
+}
+
 func setupNginxPanel(cfg *Config) {
 	panelNginxConf := filepath.Join(cfg.PanelDir, "nginx.conf")
 	if !fileExists(panelNginxConf) {
@@ -105,6 +294,7 @@ func setupNginxPanel(cfg *Config) {
 	runShellSilent(fmt.Sprintf(`cp "%s" "%s.backup.$(date +%%Y%%m%%d_%%H%%M%%S)"`, panelNginxConf, panelNginxConf))
 	runShellSilent(fmt.Sprintf(`sed -i '/# === BEGIN Bedolaga Bot ===/,/# === END Bedolaga Bot ===/d' "%s"`, panelNginxConf))
 
+	upstream := webAPIUpstream(cfg)
 	block := "\n# === BEGIN Bedolaga Bot ===\n"
 	if cfg.WebhookDomain != "" {
 		block += fmt.Sprintf(`server {
@@ -115,7 +305,7 @@ func setupNginxPanel(cfg *Config) {
     ssl_certificate_key "/etc/letsencrypt/live/%s/privkey.pem";
     client_max_body_size 32m;
     location / {
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_http_version 1.1;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
@@ -128,7 +318,7 @@ func setupNginxPanel(cfg *Config) {
         proxy_buffering off;
     }
 }
-`, cfg.WebhookDomain, cfg.WebhookDomain, cfg.WebhookDomain)
+`, cfg.WebhookDomain, cfg.WebhookDomain, cfg.WebhookDomain, upstream)
 	}
 	if cfg.MiniappDomain != "" {
 		block += fmt.Sprintf(`server {
@@ -139,7 +329,7 @@ func setupNginxPanel(cfg *Config) {
     ssl_certificate_key "/etc/letsencrypt/live/%s/privkey.pem";
     client_max_body_size 32m;
     location /miniapp/ {
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_http_version 1.1;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
@@ -150,7 +340,7 @@ func setupNginxPanel(cfg *Config) {
     }
     location = /app-config.json {
         add_header Access-Control-Allow-Origin "*";
-        proxy_pass http://127.0.0.1:8080;
+        proxy_pass http://%s;
         proxy_set_header Host $host;
         proxy_set_header X-Real-IP $remote_addr;
     }
@@ -161,7 +351,7 @@ func setupNginxPanel(cfg *Config) {
         add_header Cache-Control "public, immutable";
     }
 }
-`, cfg.MiniappDomain, cfg.MiniappDomain, cfg.MiniappDomain)
+`, cfg.MiniappDomain, cfg.MiniappDomain, cfg.MiniappDomain, upstream, upstream)
 	}
 	block += "# === END Bedolaga Bot ===\n"
 
@@ -181,10 +371,14 @@ func setupNginxPanel(cfg *Config) {
 
 func setupCaddy(cfg *Config) {
 	// Останавливаем nginx/apache если запущены (они занимают порт 80)
-	runShellSilent("systemctl stop nginx 2>/dev/null || true")
-	runShellSilent("systemctl disable nginx 2>/dev/null || true")
-	runShellSilent("systemctl stop apache2 2>/dev/null || true")
-	runShellSilent("systemctl disable apache2 2>/dev/null || true")
+	runShellSilent(serviceCmd("stop", "nginx") + " 2>/dev/null || true")
+	runShellSilent(serviceCmd("disable", "nginx") + " 2>/dev/null || true")
+	apache := "apache2"
+	if pkgManager().rhelFamily() {
+		apache = "httpd"
+	}
+	runShellSilent(serviceCmd("stop", apache) + " 2>/dev/null || true")
+	runShellSilent(serviceCmd("disable", apache) + " 2>/dev/null || true")
 	// Останавливаем remnawave-nginx если есть (он тоже занимает порт 80)
 	runShellSilent("docker stop remnawave-nginx 2>/dev/null || true")
 
@@ -201,32 +395,33 @@ func createCaddyfile(cfg *Config) {
 
 	var content string
 
-	// Используем 127.0.0.1:8080 т.к. Caddy работает в host network mode
+	// Caddy работает в host network mode — бот доступен по адресу публикации web API
+	upstream := webAPIUpstream(cfg)
 	if cfg.WebhookDomain != "" {
 		content += fmt.Sprintf(`%s {
-    reverse_proxy 127.0.0.1:8080 {
+    reverse_proxy %s {
         flush_interval -1
     }
 }
 
-`, cfg.WebhookDomain)
+`, cfg.WebhookDomain, upstream)
 	}
 
 	if cfg.MiniappDomain != "" {
 		content += fmt.Sprintf(`%s {
     @api path /miniapp/*
-    reverse_proxy @api 127.0.0.1:8080 {
+    reverse_proxy @api %s {
         flush_interval -1
     }
     @config path /app-config.json
-    reverse_proxy @config 127.0.0.1:8080
+    reverse_proxy @config %s
     header @config Access-Control-Allow-Origin *
     root * %s/miniapp
     try_files {path} {path}/ /index.html
     file_server
 }
 
-`, cfg.MiniappDomain, cfg.InstallDir)
+`, cfg.MiniappDomain, upstream, upstream, cfg.InstallDir)
 	}
 
 	os.WriteFile(filepath.Join(caddyDir, "Caddyfile"), []byte(content), 0644)
@@ -234,32 +429,12 @@ func createCaddyfile(cfg *Config) {
 
 func createCaddyCompose(cfg *Config) {
 	// Caddy использует host network mode для доступа к интернету (Let's Encrypt)
-	// и к боту на 127.0.0.1:8080
-	miniappVolume := ""
+	// и к боту по адресу публикации web API
+	opts := composeOptions{Caddy: true}
 	if cfg.MiniappDomain != "" {
-		miniappVolume = fmt.Sprintf(`
-      - %s/miniapp:/srv/miniapp:ro`, cfg.InstallDir)
-	}
-
-	content := fmt.Sprintf(`services:
-  caddy:
-    image: caddy:2-alpine
-    container_name: remnawave_caddy
-    restart: unless-stopped
-    network_mode: host
-    volumes:
-      - ./caddy/Caddyfile:/etc/caddy/Caddyfile:ro
-      - caddy_data:/data
-      - caddy_config:/config%s
-
-volumes:
-  caddy_data:
-    driver: local
-  caddy_config:
-    driver: local
-`, miniappVolume)
-
-	os.WriteFile(filepath.Join(cfg.InstallDir, "docker-compose.caddy.yml"), []byte(content), 0644)
+		opts.MiniappDir = cfg.InstallDir + "/miniapp"
+	}
+	writeCompose(cfg, "docker-compose.caddy.yml", opts)
 }
 
 // ════════════════════════════════════════════════════════════════
@@ -280,27 +455,91 @@ func setupSSL(cfg *Config) {
 	}
 
 	cfg.SSLEmail = ui.InputText("Email Let's Encrypt", "admin@example.com", "Email для уведомлений о SSL-сертификатах", true)
+	obtainCertificates(cfg, []string{cfg.WebhookDomain, cfg.MiniappDomain})
+}
+
+// obtainCertificates получает сертификаты Let's Encrypt для доменов через certbot.
+// Email спрашивается, только если у certbot ещё нет учётной записи
+func obtainCertificates(cfg *Config, domains []string) error {
+	if strings.Join(domains, "") == "" {
+		return nil
+	}
+	if cfg.SSLEmail == "" && !dirExists("/etc/letsencrypt/accounts") {
+		cfg.SSLEmail = ui.InputText("Email Let's Encrypt", "admin@example.com", "Email для уведомлений о SSL-сертификатах", true)
+	}
+	emailFlag := ""
+	if cfg.SSLEmail != "" {
+		emailFlag = " --email " + cfg.SSLEmail
+	}
 
 	isPanelMode := cfg.ReverseProxyType == "nginx_panel"
 
-	for _, domain := range []string{cfg.WebhookDomain, cfg.MiniappDomain} {
+	var failed []string
+	for _, domain := range domains {
 		if domain == "" {
 			continue
 		}
-		ui.RunWithSpinner("Получение SSL для "+domain+"...", func() error {
+		err := ui.RunWithSpinner("Получение SSL для "+domain+"...", func() error {
 			if isPanelMode {
 				runShellSilent("docker stop remnawave-nginx 2>/dev/null || true")
-				runShellSilent("systemctl stop nginx 2>/dev/null || true")
+				runShellSilent(serviceCmd("stop", "nginx") + " 2>/dev/null || true")
 				time.Sleep(2 * time.Second)
-				err := runShell(fmt.Sprintf("certbot certonly --standalone -d %s --email %s --agree-tos --non-interactive", domain, cfg.SSLEmail))
+				err := runShell(fmt.Sprintf("certbot certonly --standalone -d %s%s --agree-tos --non-interactive", domain, emailFlag))
 				runShellSilent("docker start remnawave-nginx 2>/dev/null || true")
-				runShellSilent("systemctl start nginx 2>/dev/null || true")
+				runShellSilent(serviceCmd("start", "nginx") + " 2>/dev/null || true")
 				return err
 			}
-			return runShell(fmt.Sprintf("certbot --nginx -d %s --email %s --agree-tos --non-interactive", domain, cfg.SSLEmail))
+			return runShell(fmt.Sprintf("certbot --nginx -d %s%s --agree-tos --non-interactive", domain, emailFlag))
 		})
+		if err != nil {
+			failed = append(failed, domain)
+		}
 	}
 
-	runShellSilent("systemctl enable certbot.timer 2>/dev/null || true")
-	runShellSilent("systemctl start certbot.timer 2>/dev/null || true")
+	enableCertRenewal()
+	if len(failed) > 0 {
+		return fmt.Errorf("сертификат не получен: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// refreshCertificates — сертификаты после пересоздания сайтов (bot mode, bot domain).
+// Системный nginx: certbot заново прописывает TLS во все сайты бота, nginx панели
+// ссылается на /etc/letsencrypt/live напрямую — нужны только новые домены. Caddy — сам
+func refreshCertificates(cfg *Config, newDomains ...string) error {
+	var domains []string
+	switch cfg.ReverseProxyType {
+	case "nginx_system":
+		domains = []string{cfg.WebhookDomain, cfg.MiniappDomain}
+	case "nginx_panel":
+		domains = newDomains
+	default:
+		return nil
+	}
+	return obtainCertificates(cfg, domains)
+}
+
+const certRenewPeriodic = "/etc/periodic/daily/bedolaga-certbot-renew"
+
+// enableCertRenewal включает автопродление сертификатов: certbot.timer (Debian/Ubuntu),
+// certbot-renew.timer (EPEL на dnf/yum), ежедневное задание crond на Alpine
+func enableCertRenewal() {
+	pm := pkgManager()
+	switch {
+	case pm.Name == "apk":
+		os.WriteFile(certRenewPeriodic, []byte("#!/bin/sh\ncertbot renew -q\n"), 0755)
+		runShellSilent(serviceCmd("enable", "crond") + " 2>/dev/null || true")
+		runShellSilent(serviceCmd("start", "crond") + " 2>/dev/null || true")
+	case pm.rhelFamily():
+		runShellSilent("systemctl enable --now certbot-renew.timer 2>/dev/null || true")
+	default:
+		runShellSilent("systemctl enable --now certbot.timer 2>/dev/null || true")
+	}
+}
+
+// deleteCertificate удаляет сертификат домена, который больше не обслуживается
+func deleteCertificate(domain string) {
+	if dirExists("/etc/letsencrypt/live/" + domain) {
+		runShellSilent(fmt.Sprintf("certbot delete --cert-name %s --non-interactive 2>&1", domain))
+	}
 }
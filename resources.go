@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// RESOURCE PROFILES (Postgres / Redis / limits)
+// ════════════════════════════════════════════════════════════════
+
+type resourceProfile struct {
+	Name        string
+	Title       string
+	Description string
+	MaxMemMB    int // профиль рекомендуется, если RAM хоста не больше этого значения
+
+	RedisMaxMemory   string
+	PGSharedBuffers  string
+	PGWorkMem        string
+	PGMaxConnections int
+
+	Postgres composeResources
+	Redis    composeResources
+	Bot      composeResources
+}
+
+var resourceProfiles = []resourceProfile{
+	{
+		Name: "small", Title: "Small", Description: "VPS ~1 ГБ RAM, 1 CPU", MaxMemMB: 1536,
+		RedisMaxMemory: "64mb", PGSharedBuffers: "64MB", PGWorkMem: "2MB", PGMaxConnections: 50,
+		Postgres: composeResources{MemLimit: "256m", CPUs: "0.5"},
+		Redis:    composeResources{MemLimit: "96m", CPUs: "0.25"},
+		Bot:      composeResources{MemLimit: "512m", CPUs: "1.0"},
+	},
+	{
+		Name: "medium", Title: "Medium", Description: "VPS 2–4 ГБ RAM, 2 CPU", MaxMemMB: 4608,
+		RedisMaxMemory: "256mb", PGSharedBuffers: "256MB", PGWorkMem: "4MB", PGMaxConnections: 100,
+		Postgres: composeResources{MemLimit: "1g", CPUs: "1.0"},
+		Redis:    composeResources{MemLimit: "320m", CPUs: "0.5"},
+		Bot:      composeResources{MemLimit: "1g", CPUs: "1.5"},
+	},
+	{
+		Name: "large", Title: "Large", Description: "VPS 8+ ГБ RAM, 4+ CPU",
+		RedisMaxMemory: "512mb", PGSharedBuffers: "1GB", PGWorkMem: "8MB", PGMaxConnections: 200,
+		Postgres: composeResources{MemLimit: "3g", CPUs: "2.0"},
+		Redis:    composeResources{MemLimit: "640m", CPUs: "1.0"},
+		Bot:      composeResources{MemLimit: "2g", CPUs: "2.0"},
+	},
+}
+
+func findResourceProfile(name string) (resourceProfile, bool) {
+	for _, p := range resourceProfiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return resourceProfile{}, false
+}
+
+// recommendResourceProfile подбирает профиль по объёму RAM хоста
+func recommendResourceProfile(memMB int) resourceProfile {
+	for _, p := range resourceProfiles {
+		if p.MaxMemMB > 0 && memMB <= p.MaxMemMB {
+			return p
+		}
+	}
+	return resourceProfiles[len(resourceProfiles)-1]
+}
+
+// hostMemoryMB читает MemTotal из /proc/meminfo
+func hostMemoryMB() int {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	return parseMemTotalMB(string(data))
+}
+
+func parseMemTotalMB(meminfo string) int {
+	return parseMeminfoMB(meminfo, "MemTotal")
+}
+
+// parseMeminfoMB возвращает поле /proc/meminfo (MemTotal, MemAvailable, SwapTotal...) в МБ
+func parseMeminfoMB(meminfo, field string) int {
+	for _, line := range strings.Split(meminfo, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == field+":" {
+			kb, _ := strconv.Atoi(fields[1])
+			return kb / 1024
+		}
+	}
+	return 0
+}
+
+// applyResourceProfile переносит профиль в опции compose; cpus не превышает число CPU хоста
+func applyResourceProfile(opts *composeOptions, p resourceProfile, hostCPUs int) {
+	opts.RedisMaxMemory = p.RedisMaxMemory
+	opts.PostgresCommand = fmt.Sprintf("postgres -c shared_buffers=%s -c work_mem=%s -c max_connections=%d",
+		p.PGSharedBuffers, p.PGWorkMem, p.PGMaxConnections)
+	opts.Postgres = clampCPUs(p.Postgres, hostCPUs)
+	opts.Redis = clampCPUs(p.Redis, hostCPUs)
+	opts.Bot = clampCPUs(p.Bot, hostCPUs)
+}
+
+func clampCPUs(r composeResources, hostCPUs int) composeResources {
+	if cpus, err := strconv.ParseFloat(r.CPUs, 64); err == nil && hostCPUs > 0 && cpus > float64(hostCPUs) {
+		r.CPUs = fmt.Sprintf("%d.0", hostCPUs)
+	}
+	return r
+}
+
+// selectResourceProfile предлагает профиль ресурсов по характеристикам сервера
+func selectResourceProfile(cfg *Config) {
+	memMB, cpus := hostMemoryMB(), runtime.NumCPU()
+	recommended := recommendResourceProfile(memMB)
+	ui.PrintInfo(fmt.Sprintf("Сервер: %d МБ RAM, %d CPU — рекомендуется профиль %s", memMB, cpus, recommended.Title))
+
+	items := []ui.SelectItem{{Title: recommended.Title + " (рекомендуется)", Description: recommended.Description}}
+	names := []string{recommended.Name}
+	for _, p := range resourceProfiles {
+		if p.Name != recommended.Name {
+			items = append(items, ui.SelectItem{Title: p.Title, Description: p.Description})
+			names = append(names, p.Name)
+		}
+	}
+	items = append(items, ui.SelectItem{Title: "Без ограничений", Description: "Настройки PostgreSQL/Redis по умолчанию, без лимитов контейнеров"})
+	names = append(names, "none")
+
+	idx := ui.SelectOption("Профиль ресурсов", items)
+	cfg.ResourceProfile = names[idx]
+}
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: TUNE
+// ════════════════════════════════════════════════════════════════
+
+func manageTune(installDir, composeFile string) {
+	cfg, ok := loadInstallConfig(installDir)
+	if !ok {
+		ui.PrintError("В .env нет секции INSTALLER — установка создана старой версией, выполните переустановку")
+		return
+	}
+	current := cfg.ResourceProfile
+	if current == "" {
+		current = "none"
+	}
+	ui.PrintInfo("Текущий профиль: " + current)
+
+	selectResourceProfile(cfg)
+	if err := updateEnvFile(filepath.Join(installDir, ".env"), map[string]string{"INSTALLER_RESOURCE_PROFILE": cfg.ResourceProfile}); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		return
+	}
+	composeFile = writeBotCompose(cfg)
+
+	var out string
+	err := ui.RunWithSpinner("Применение профиля...", func() error {
+		var err error
+		out, err = runShellSilent(composeCmd(installDir, composeFile) + " up -d 2>&1")
+		return err
+	})
+	if err != nil {
+		ui.PrintError("Профиль не применён: " + lastLine(out))
+		return
+	}
+	ui.PrintSuccess("Профиль ресурсов применён: " + cfg.ResourceProfile)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: ROTATE SECRETS
+// ════════════════════════════════════════════════════════════════
+
+// secretRotation — какие секреты менять: bot rotate-secrets [--postgres] [--webhook] [--webapi] [--jwt] | --all
+type secretRotation struct {
+	Postgres bool
+	Webhook  bool
+	WebAPI   bool
+	JWT      bool
+}
+
+func parseRotateArgs(args []string) (secretRotation, error) {
+	var r secretRotation
+	for _, a := range args {
+		switch a {
+		case "--postgres":
+			r.Postgres = true
+		case "--webhook":
+			r.Webhook = true
+		case "--webapi":
+			r.WebAPI = true
+		case "--jwt":
+			r.JWT = true
+		case "--all":
+			r = secretRotation{true, true, true, true}
+		default:
+			return r, fmt.Errorf("неизвестный флаг: %s", a)
+		}
+	}
+	// без флагов ничего не меняется: смена пароля БД — ALTER USER в работающей базе
+	if r == (secretRotation{}) {
+		return r, fmt.Errorf("укажите, какие секреты заменить, или --all")
+	}
+	return r, nil
+}
+
+// alterPostgresPassword меняет пароль пользователя бота в работающей БД (SQL через stdin,
+// чтобы пароль не попал в список процессов)
+func alterPostgresPassword(cfg *Config, composeFile, password string) error {
+	sql := fmt.Sprintf(`ALTER USER "%s" WITH PASSWORD '%s';`, strings.ReplaceAll(postgresUser(cfg), `"`, `""`), password)
+	psql, env := pgClientCmd(cfg, composeFile, "psql")
+	cmd := fmt.Sprintf("printf '%%s\\n' %s | %s -d %s -v ON_ERROR_STOP=1 2>&1", shellQuote(sql), psql, shellQuote(postgresDB(cfg)))
+	out, err := runShellSilentEnv(cmd, env...)
+	if err != nil || !strings.Contains(out, "ALTER ROLE") {
+		return fmt.Errorf("%s", lastLine(out))
+	}
+	return nil
+}
+
+func manageRotateSecrets(installDir, composeFile string, args []string) {
+	rot, err := parseRotateArgs(args)
+	if err != nil {
+		ui.PrintError(err.Error())
+		ui.PrintDim("Использование: bot rotate-secrets [--postgres] [--webhook] [--webapi] [--jwt] | --all")
+		return
+	}
+	envPath := filepath.Join(installDir, ".env")
+	cfg, _ := loadInstallConfig(installDir)
+	env := readEnvFile(envPath)
+
+	var names []string
+	if rot.Postgres {
+		names = append(names, "пароль PostgreSQL")
+	}
+	if rot.Webhook {
+		names = append(names, "WEBHOOK_SECRET_TOKEN")
+	}
+	if rot.WebAPI {
+		names = append(names, "WEB_API_DEFAULT_TOKEN")
+	}
+	if rot.JWT {
+		names = append(names, "CABINET_JWT_SECRET")
+	}
+	fmt.Println()
+	ui.PrintInfo("Будут заменены: " + strings.Join(names, ", "))
+	if rot.JWT {
+		ui.PrintDim("Новый CABINET_JWT_SECRET завершит все сессии личного кабинета")
+	}
+	if rot.WebAPI {
+		ui.PrintDim("Клиенты web API со старым токеном перестанут работать")
+	}
+	if rot.Postgres {
+		ui.PrintDim("Пароль PostgreSQL меняется в работающей базе (ALTER USER), бот будет перезапущен")
+	}
+	if !ui.ConfirmPrompt("Продолжить?", true) {
+		return
+	}
+
+	runShellSilent(fmt.Sprintf(`cd %s && cp .env ".env.backup_$(date +%%Y%%m%%d_%%H%%M%%S)" 2>/dev/null || true`, installDir))
+
+	updates := map[string]string{}
+	if rot.Postgres {
+		password := generateSafePassword(24)
+		err := ui.RunWithSpinner("Смена пароля PostgreSQL...", func() error {
+			return alterPostgresPassword(cfg, composeFile, password)
+		})
+		if err != nil {
+			ui.PrintError("ALTER USER не выполнен, пароль не изменён: " + err.Error())
+			return
+		}
+		updates["POSTGRES_PASSWORD"] = password
+	}
+	if rot.Webhook {
+		updates["WEBHOOK_SECRET_TOKEN"] = generateToken()
+	}
+	if rot.WebAPI {
+		updates["WEB_API_DEFAULT_TOKEN"] = generateToken()
+	}
+	if rot.JWT {
+		updates["CABINET_JWT_SECRET"] = generateToken()
+	}
+
+	if err := updateEnvFile(envPath, updates); err != nil {
+		ui.PrintError("Ошибка записи .env: " + err.Error())
+		if pw := updates["POSTGRES_PASSWORD"]; pw != "" {
+			ui.PrintWarning("Пароль БД уже изменён — впишите его в .env вручную: POSTGRES_PASSWORD=" + pw)
+		}
+		return
+	}
+	ui.PrintSuccess(".env обновлён")
+
+	// up -d, а не restart: переменные из .env подставляются в контейнер только при пересоздании
+	if err := recreateBot(installDir, composeFile); err != nil {
+		ui.PrintError("Ошибка перезапуска: " + err.Error())
+		return
+	}
+
+	if rot.Webhook && env["BOT_RUN_MODE"] == "webhook" {
+		registerWebhook(cfg)
+	}
+	ui.PrintSuccess("Секреты заменены, старый .env сохранён в .env.backup_*")
+}
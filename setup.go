@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -113,48 +116,138 @@ func inputDomainSafe(label, hint string) string {
 		if val == "" {
 			return ""
 		}
-		val = cleanDomain(val)
-		if !validateDomain(val) {
-			ui.PrintError("Неверный формат домена: " + val)
-			ui.PrintDim("Ожидаемый формат: bot.example.com")
-			if !ui.IsInteractive() {
-				return ""
+		if domain, retry := checkDomainSafe(val); !retry {
+			return domain
+		}
+	}
+}
+
+// checkDomainSafe проверяет формат домена и DNS. Возвращает домен ("" — пропустить)
+// или retry=true, если пользователь хочет ввести другой
+func checkDomainSafe(val string) (domain string, retry bool) {
+	val = cleanDomain(val)
+	if !validateDomain(val) {
+		ui.PrintError("Неверный формат домена: " + val)
+		ui.PrintDim("Ожидаемый формат: bot.example.com")
+		if !ui.IsInteractive() {
+			return "", false
+		}
+		idx := ui.SelectOption("Что делать?", []ui.SelectItem{
+			{Title: "Попробовать снова", Description: "Ввести другой домен"},
+			{Title: "Использовать всё равно", Description: "Продолжить с этим значением"},
+			{Title: "Пропустить", Description: "Не настраивать этот домен"},
+		})
+		switch idx {
+		case 0:
+			return "", true
+		case 2:
+			return "", false
+		}
+		return val, false
+	}
+	ui.PrintInfo("Проверка DNS...")
+	if !checkDomainDNS(val) {
+		ui.PrintWarning("DNS не указывает на этот сервер")
+		if !ui.IsInteractive() {
+			return val, false
+		}
+		idx := ui.SelectOption("Что делать?", []ui.SelectItem{
+			{Title: "Попробовать другой домен", Description: "Ввести другой домен"},
+			{Title: "Продолжить с этим доменом", Description: "DNS можно настроить позже"},
+			{Title: "Пропустить", Description: "Не настраивать этот домен"},
+		})
+		switch idx {
+		case 0:
+			return "", true
+		case 2:
+			return "", false
+		}
+	}
+	return val, false
+}
+
+// selectWebAPIBind выбирает адрес, на котором публикуется порт web API.
+// За обратным прокси порт доступен только с localhost.
+func selectWebAPIBind(cfg *Config) {
+	local := ui.SelectItem{Title: "127.0.0.1", Description: "Только локально — доступ через обратный прокси (рекомендуется)"}
+	public := ui.SelectItem{Title: "0.0.0.0", Description: "Все интерфейсы — web API доступен напрямую по IP сервера"}
+	custom := ui.SelectItem{Title: "Свой адрес", Description: "Указать IP-адрес интерфейса"}
+
+	items := []ui.SelectItem{local, public, custom}
+	if cfg.ReverseProxyType == "skip" {
+		items = []ui.SelectItem{public, local, custom}
+	}
+	idx := ui.SelectOption("Публикация web API", items)
+	switch items[idx].Title {
+	case local.Title:
+		cfg.WebAPIBind = "127.0.0.1"
+	case public.Title:
+		cfg.WebAPIBind = ""
+	default:
+		for {
+			bind := ui.InputText("Адрес публикации web API", "10.0.0.5", "IP-адрес, на котором будет открыт порт", true)
+			if net.ParseIP(bind) != nil {
+				cfg.WebAPIBind = bind
+				return
 			}
-			idx := ui.SelectOption("Что делать?", []ui.SelectItem{
-				{Title: "Попробовать снова", Description: "Ввести другой домен"},
-				{Title: "Использовать всё равно", Description: "Продолжить с этим значением"},
-				{Title: "Пропустить", Description: "Не настраивать этот домен"},
-			})
-			switch idx {
-			case 0:
-				continue
-			case 1:
-				return val
-			case 2:
-				return ""
+			ui.PrintError("Неверный IP-адрес: " + bind)
+			if !ui.IsInteractive() {
+				cfg.WebAPIBind = "127.0.0.1"
+				return
 			}
 		}
-		ui.PrintInfo("Проверка DNS...")
-		if !checkDomainDNS(val) {
-			ui.PrintWarning("DNS не указывает на этот сервер")
+	}
+}
+
+// selectWebAPIPort запрашивает порт web API и проверяет, что он свободен
+func selectWebAPIPort(cfg *Config) {
+	for {
+		port := ui.InputText("Порт web API (необязательно)", defaultWebAPIPort, "Оставьте пустым для порта "+defaultWebAPIPort, false)
+		if port == "" {
+			port = defaultWebAPIPort
+		}
+		if !validPort(port) {
+			ui.PrintError("Неверный порт: " + port)
 			if !ui.IsInteractive() {
-				return val
-			}
-			idx := ui.SelectOption("Что делать?", []ui.SelectItem{
-				{Title: "Попробовать другой домен", Description: "Ввести другой домен"},
-				{Title: "Продолжить с этим доменом", Description: "DNS можно настроить позже"},
-				{Title: "Пропустить", Description: "Не настраивать этот домен"},
-			})
-			switch idx {
-			case 0:
+				port = defaultWebAPIPort
+			} else {
 				continue
-			case 1:
-				return val
-			case 2:
-				return ""
 			}
 		}
-		return val
+		if !portInUse(port) || portUsedByBot(port) {
+			cfg.WebAPIPort = port
+			return
+		}
+
+		ui.PrintWarning(describePortOwner(port))
+		free := findFreePort(port)
+		if !ui.IsInteractive() {
+			// без терминала спросить нельзя: свободный порт или запрошенный с предупреждением
+			if free == "" {
+				cfg.WebAPIPort = port
+				ui.PrintWarning("Свободный порт не найден — оставлен " + port + ", освободите его до запуска")
+				return
+			}
+			cfg.WebAPIPort = free
+			ui.PrintInfo("Используется свободный порт " + free)
+			return
+		}
+		items := []ui.SelectItem{
+			{Title: "Ввести другой порт", Description: "Указать порт вручную"},
+			{Title: "Оставить " + port, Description: "Освободить порт самостоятельно до запуска"},
+		}
+		if free != "" {
+			items = append([]ui.SelectItem{{Title: "Использовать " + free, Description: "Ближайший свободный порт"}}, items...)
+		}
+		idx := ui.SelectOption("Что делать?", items)
+		switch items[idx].Title {
+		case "Использовать " + free:
+			cfg.WebAPIPort = free
+			return
+		case "Оставить " + port:
+			cfg.WebAPIPort = port
+			return
+		}
 	}
 }
 
@@ -165,6 +258,7 @@ func checkPostgresVolume(cfg *Config) {
 	if fileExists(filepath.Join(cfg.InstallDir, ".env")) {
 		if out, err := runShellSilent(fmt.Sprintf(`grep "^POSTGRES_PASSWORD=" "%s/.env" 2>/dev/null | cut -d'=' -f2- | tr -d '"' | tr -d "'"`, cfg.InstallDir)); err == nil && out != "" {
 			cfg.OldPostgresPassword = out
+			ui.RegisterSecret(out)
 		}
 	}
 
@@ -183,47 +277,152 @@ func checkPostgresVolume(cfg *Config) {
 	}
 }
 
-func interactiveSetup(cfg *Config) {
-	ui.PrintBox("⚙️  Интерактивная настройка",
-		"Введите необходимые данные для настройки бота.\n"+
-			ui.DimStyle.Render("Необязательные поля можно пропустить клавишей Esc."))
-
-	cfg.BotToken = ui.InputText("BOT_TOKEN", "123456:ABC-DEF...", "Получить у @BotFather в Telegram", true)
-	cfg.AdminIDs = ui.InputText("ADMIN_IDS", "123456789", "Ваш Telegram ID (несколько: 123,456). Узнать у @userinfobot", true)
+// inputBotToken запрашивает BOT_TOKEN и проверяет его через getMe. Формат — только
+// подсказка: решает Telegram. Без доступа к api.telegram.org (офлайн, блокировка)
+// токен принимается с предупреждением
+func inputBotToken(cfg *Config) string {
+	for {
+		token := ui.InputSecret("BOT_TOKEN", "123456:ABC-DEF...", "Получить у @BotFather в Telegram", true)
+		if !validBotToken(token) {
+			ui.PrintWarning("Необычный формат токена: ожидается 123456789:AAH... (35 символов после двоеточия)")
+		}
+		if cfg.Offline {
+			ui.PrintDim("Офлайн-установка: токен не проверяется через Telegram")
+			return token
+		}
 
-	if cfg.PanelInstalledLocally && cfg.DockerNetwork != "" {
-		ui.PrintInfo("Локальная панель — используется внутренний Docker-адрес")
-		val := ui.InputText("REMNAWAVE_API_URL", "http://remnawave:3000", "Внутренний адрес для локальной панели", false)
-		if val == "" {
-			val = "http://remnawave:3000"
+		var bot *telegramBot
+		err := ui.RunWithSpinner("Проверка токена (getMe)...", func() error {
+			var err error
+			bot, err = telegramGetMe(token)
+			return err
+		})
+		var apiErr *telegramAPIError
+		switch {
+		case err == nil:
+			ui.PrintSuccess("Бот: " + bot.String())
+			if ui.ConfirmPrompt("Это ваш бот?", true) {
+				return token
+			}
+		case errors.As(err, &apiErr):
+			ui.PrintError("Telegram отклонил токен: " + apiErr.Description)
+			ui.PrintDim("Проверьте токен у @BotFather (/mybots → API Token)")
+			if !ui.IsInteractive() {
+				globalProgress.fail("Неверный BOT_TOKEN")
+				os.Exit(1)
+			}
+		default:
+			ui.PrintWarning("Не удалось связаться с Telegram: " + err.Error())
+			if ui.ConfirmPrompt("Продолжить без проверки токена?", true) {
+				return token
+			}
 		}
-		cfg.RemnawaveAPIURL = val
-	} else {
-		cfg.RemnawaveAPIURL = ui.InputText("REMNAWAVE_API_URL", "https://panel.yourdomain.com", "Внешний URL панели Remnawave", true)
 	}
+}
 
-	cfg.RemnawaveAPIKey = ui.InputText("REMNAWAVE_API_KEY", "", "Получить в настройках панели Remnawave", true)
+// Варианты доступа к API панели
+var panelAuthOptions = []ui.SelectItem{
+	{Title: "API Key", Description: "Панель открыта напрямую — достаточно API-ключа"},
+	{Title: "API Key + Secret Key", Description: "Панель защищена секретным ключом (скрипт eGames, cookie)"},
+	{Title: "API Key + Basic Auth", Description: "Панель за прокси с логином и паролем"},
+	{Title: "API Key + Basic Auth + Secret Key", Description: "Оба вида защиты одновременно"},
+}
+
+// selectPanelAuth выбирает комбинацию авторизации и запрашивает нужные данные;
+// невыбранные поля очищаются, чтобы не попасть в .env при повторном вводе
+func selectPanelAuth(cfg *Config) {
+	idx := ui.SelectOption("Тип авторизации", panelAuthOptions)
+	basic := idx == 2 || idx == 3
+	secret := idx == 1 || idx == 3
 
-	idx := ui.SelectOption("Тип авторизации", []ui.SelectItem{
-		{Title: "API Key", Description: "По умолчанию — только API-ключ"},
-		{Title: "Basic Auth", Description: "Авторизация по логину и паролю"},
-	})
 	cfg.RemnawaveAuthType = "api_key"
-	if idx == 1 {
+	cfg.RemnawaveUsername, cfg.RemnawavePassword, cfg.RemnawaveSecretKey = "", "", ""
+	if basic {
 		cfg.RemnawaveAuthType = "basic_auth"
-		cfg.RemnawaveUsername = ui.InputText("REMNAWAVE_USERNAME", "", "", false)
-		cfg.RemnawavePassword = ui.InputText("REMNAWAVE_PASSWORD", "", "", false)
+		cfg.RemnawaveUsername = ui.InputText("REMNAWAVE_USERNAME", "", "Логин Basic Auth перед панелью", true)
+		cfg.RemnawavePassword = ui.InputSecret("REMNAWAVE_PASSWORD", "", "Пароль Basic Auth", true)
+	}
+	if secret {
+		cfg.RemnawaveSecretKey = ui.InputSecret("REMNAWAVE_SECRET_KEY", "имя:значение",
+			"Из ссылки входа https://panel.example.com/auth/login?имя=значение", true)
+		cfg.RemnawaveSecretKey = normalizeSecretKey(cfg.RemnawaveSecretKey)
+	}
+}
+
+// normalizeSecretKey приводит вставленный кусок ссылки «имя=значение»
+// (или всю ссылку) к формату бота «имя:значение»
+func normalizeSecretKey(key string) string {
+	if _, query, ok := strings.Cut(key, "?"); ok {
+		key = query
+	}
+	if strings.Contains(key, ":") || strings.HasSuffix(key, "=") {
+		return key
+	}
+	if name, value, ok := strings.Cut(key, "="); ok && name != "" {
+		return name + ":" + value
+	}
+	return key
+}
+
+// inputPanelSettings запрашивает адрес и доступ к API панели и проверяет их
+// авторизованным запросом; при ошибке предлагает ввести данные заново
+func inputPanelSettings(cfg *Config) {
+	for {
+		if cfg.PanelInstalledLocally && cfg.DockerNetwork != "" {
+			ui.PrintInfo("Локальная панель — используется внутренний Docker-адрес")
+			val := ui.InputText("REMNAWAVE_API_URL", "http://remnawave:3000", "Внутренний адрес для локальной панели", false)
+			if val == "" {
+				val = "http://remnawave:3000"
+			}
+			cfg.RemnawaveAPIURL = val
+		} else {
+			cfg.RemnawaveAPIURL = ui.InputText("REMNAWAVE_API_URL", "https://panel.yourdomain.com", "Внешний URL панели Remnawave", true)
+		}
+
+		cfg.RemnawaveAPIKey = ui.InputSecret("REMNAWAVE_API_KEY", "", "Получить в настройках панели Remnawave", true)
+
+		selectPanelAuth(cfg)
+
+		if cfg.Offline {
+			ui.PrintDim("Офлайн-установка: подключение к панели не проверяется")
+			return
+		}
+		if checkPanelAPI(cfg) || !ui.IsInteractive() {
+			return
+		}
+		idx := ui.SelectOption("Что делать?", []ui.SelectItem{
+			{Title: "Ввести заново", Description: "Указать другой URL или данные доступа"},
+			{Title: "Продолжить", Description: "Сохранить как есть — панель можно проверить позже в bot health"},
+		})
+		if idx == 1 {
+			return
+		}
 	}
+}
+
+func interactiveSetup(cfg *Config) {
+	ui.PrintBox("⚙️  Интерактивная настройка",
+		"Введите необходимые данные для настройки бота.\n"+
+			ui.DimStyle.Render("Необязательные поля можно пропустить клавишей Esc."))
+
+	cfg.BotToken = inputBotToken(cfg)
+	cfg.AdminIDs = ui.InputText("ADMIN_IDS", "123456789", "Ваш Telegram ID (несколько: 123,456). Узнать у @userinfobot", true)
+
+	inputPanelSettings(cfg)
 
 	cfg.WebhookDomain = inputDomainSafe("Домен вебхука (необязательно)", "Для режима webhook. Оставьте пустым для polling.")
 	cfg.MiniappDomain = inputDomainSafe("Домен Mini App (необязательно)", "Домен для Telegram Mini App")
 	cfg.AdminNotificationsChatID = ui.InputText("Chat ID уведомлений (необязательно)", "-1001234567890", "ID чата/группы Telegram для уведомлений администратора", false)
 
-	if cfg.KeepExistingVolumes && cfg.OldPostgresPassword != "" {
+	selectDatabaseMode(cfg)
+
+	if externalPostgres(cfg) {
+		ui.PrintInfo("PostgreSQL: внешний сервер " + postgresHost(cfg))
+	} else if cfg.KeepExistingVolumes && cfg.OldPostgresPassword != "" {
 		cfg.PostgresPassword = cfg.OldPostgresPassword
 		ui.PrintSuccess("PostgreSQL: используется сохранённый пароль")
 	} else {
-		pw := ui.InputText("Пароль PostgreSQL (необязательно)", "", "Оставьте пустым для автогенерации безопасного пароля", false)
+		pw := ui.InputSecret("Пароль PostgreSQL (необязательно)", "", "Оставьте пустым для автогенерации безопасного пароля", false)
 		if pw == "" {
 			cfg.PostgresPassword = generateSafePassword(24)
 			ui.PrintSuccess("Сгенерирован безопасный пароль PostgreSQL")
@@ -233,39 +432,19 @@ func interactiveSetup(cfg *Config) {
 	}
 
 	if cfg.WebhookDomain != "" || cfg.MiniappDomain != "" {
-		proxyItems := []ui.SelectItem{
-			{Title: "Nginx (системный)", Description: "Автономный nginx на сервере"},
-			{Title: "Caddy", Description: "Автоматический HTTPS, простая настройка"},
-			{Title: "Пропустить", Description: "Настроить вручную позже"},
-		}
-		if cfg.PanelInstalledLocally {
-			nginxNet, _ := runShellSilent("docker inspect remnawave-nginx --format '{{.HostConfig.NetworkMode}}' 2>/dev/null")
-			if strings.TrimSpace(nginxNet) == "host" {
-				proxyItems = []ui.SelectItem{
-					{Title: "Nginx (панели)", Description: "Добавить в nginx панели (host mode)"},
-					{Title: "Nginx (системный)", Description: "Автономный nginx на сервере"},
-					{Title: "Caddy", Description: "Автоматический HTTPS, простая настройка"},
-					{Title: "Пропустить", Description: "Настроить вручную позже"},
-				}
-			}
-		}
-
-		idx := ui.SelectOption("Обратный прокси", proxyItems)
-		title := proxyItems[idx].Title
-		switch {
-		case strings.Contains(title, "панели"):
-			cfg.ReverseProxyType = "nginx_panel"
-		case strings.Contains(title, "системный"):
-			cfg.ReverseProxyType = "nginx_system"
-		case strings.Contains(title, "Caddy"):
-			cfg.ReverseProxyType = "caddy"
-		default:
-			cfg.ReverseProxyType = "skip"
-		}
+		selectReverseProxy(cfg)
 	} else {
 		cfg.ReverseProxyType = "skip"
 	}
 
+	checkProxyPorts(cfg)
+	selectWebAPIBind(cfg)
+	selectWebAPIPort(cfg)
+	selectResourceProfile(cfg)
+	selectLocalization(cfg)
+	selectLogRotation(cfg)
+	selectBotImage(cfg)
+
 	cfg.WebhookSecretToken = generateToken()
 	cfg.WebAPIDefaultToken = generateToken()
 	cfg.SupportUsername = "@support"
@@ -282,3 +461,37 @@ func interactiveSetup(cfg *Config) {
 
 	ui.PrintSuccessBox(ui.SuccessStyle.Render("Настройка завершена!"))
 }
+
+// selectReverseProxy — выбор обратного прокси для доменов бота; nginx панели
+// предлагается, только если он работает в host-режиме
+func selectReverseProxy(cfg *Config) {
+	proxyItems := []ui.SelectItem{
+		{Title: "Nginx (системный)", Description: "Автономный nginx на сервере"},
+		{Title: "Caddy", Description: "Автоматический HTTPS, простая настройка"},
+		{Title: "Пропустить", Description: "Настроить вручную позже"},
+	}
+	if cfg.PanelInstalledLocally {
+		nginxNet, _ := runShellSilent("docker inspect remnawave-nginx --format '{{.HostConfig.NetworkMode}}' 2>/dev/null")
+		if strings.TrimSpace(nginxNet) == "host" {
+			proxyItems = []ui.SelectItem{
+				{Title: "Nginx (панели)", Description: "Добавить в nginx панели (host mode)"},
+				{Title: "Nginx (системный)", Description: "Автономный nginx на сервере"},
+				{Title: "Caddy", Description: "Автоматический HTTPS, простая настройка"},
+				{Title: "Пропустить", Description: "Настроить вручную позже"},
+			}
+		}
+	}
+
+	idx := ui.SelectOption("Обратный прокси", proxyItems)
+	title := proxyItems[idx].Title
+	switch {
+	case strings.Contains(title, "панели"):
+		cfg.ReverseProxyType = "nginx_panel"
+	case strings.Contains(title, "системный"):
+		cfg.ReverseProxyType = "nginx_system"
+	case strings.Contains(title, "Caddy"):
+		cfg.ReverseProxyType = "caddy"
+	default:
+		cfg.ReverseProxyType = "skip"
+	}
+}
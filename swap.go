@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// SWAP
+// ════════════════════════════════════════════════════════════════
+
+const (
+	swapFilePath   = "/swapfile"
+	swapFstabMark  = "# bedolaga_installer"
+	swapSysctlConf = "/etc/sysctl.d/99-bedolaga-swap.conf"
+	swapSwappiness = 10
+)
+
+// swapSizeMB — 2×RAM в пределах 1–4 ГБ (кратно 512 МБ), не съедая минимум места на диске.
+// 0 — места не хватает
+func swapSizeMB(memMB, diskFreeMB int) int {
+	size := memMB * 2
+	if size < 1024 {
+		size = 1024
+	}
+	if size > 4096 {
+		size = 4096
+	}
+	size = (size + 511) / 512 * 512
+	if diskFreeMB >= 0 && diskFreeMB-size < minDiskMB {
+		size = (diskFreeMB - minDiskMB) / 512 * 512
+	}
+	if size < 512 {
+		return 0
+	}
+	return size
+}
+
+func swapFstabLine() string {
+	return fmt.Sprintf("%s none swap sw 0 0 %s", swapFilePath, swapFstabMark)
+}
+
+// removeSwapFstabEntry убирает из fstab только строку, добавленную установщиком
+func removeSwapFstabEntry(fstab string) (string, bool) {
+	var kept []string
+	removed := false
+	for _, line := range strings.Split(fstab, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), swapFstabMark) {
+			removed = true
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), removed
+}
+
+// addSwapFstabEntry дописывает строку установщика; последняя строка без \n
+// иначе склеилась бы с записью swap
+func addSwapFstabEntry(fstab string) string {
+	if fstab != "" && !strings.HasSuffix(fstab, "\n") {
+		fstab += "\n"
+	}
+	return fstab + swapFstabLine() + "\n"
+}
+
+// installerSwapActive — swap-файл создан установщиком (есть помеченная строка в fstab)
+func installerSwapActive() bool {
+	data, err := os.ReadFile("/etc/fstab")
+	if err != nil {
+		return false
+	}
+	_, found := removeSwapFstabEntry(string(data))
+	return found
+}
+
+func createSwapFile(sizeMB int) error {
+	if fileExists(swapFilePath) {
+		return fmt.Errorf("%s уже существует", swapFilePath)
+	}
+	steps := []string{
+		fmt.Sprintf("fallocate -l %dM %s 2>&1 || dd if=/dev/zero of=%s bs=1M count=%d status=none 2>&1", sizeMB, swapFilePath, swapFilePath, sizeMB),
+		"chmod 600 " + swapFilePath,
+		"mkswap " + swapFilePath + " 2>&1",
+		"swapon " + swapFilePath + " 2>&1",
+	}
+	for _, cmd := range steps {
+		if out, err := runShellSilent(cmd); err != nil {
+			runShellSilent("swapoff " + swapFilePath + " 2>/dev/null; rm -f " + swapFilePath)
+			return fmt.Errorf("%s", lastLine(out))
+		}
+	}
+	fstab, err := os.ReadFile("/etc/fstab")
+	if err == nil {
+		err = os.WriteFile("/etc/fstab", []byte(addSwapFstabEntry(string(fstab))), 0644)
+	}
+	if err != nil {
+		runShellSilent("swapoff " + swapFilePath + " 2>/dev/null; rm -f " + swapFilePath)
+		return err
+	}
+
+	os.WriteFile(swapSysctlConf, []byte(fmt.Sprintf("vm.swappiness=%d\n", swapSwappiness)), 0644)
+	runShellSilent(fmt.Sprintf("sysctl -w vm.swappiness=%d 2>/dev/null || true", swapSwappiness))
+	return nil
+}
+
+func removeSwapFile() error {
+	data, err := os.ReadFile("/etc/fstab")
+	if err != nil {
+		return err
+	}
+	fstab, found := removeSwapFstabEntry(string(data))
+	if !found {
+		return nil
+	}
+	if out, err := runShellSilent("swapoff " + swapFilePath + " 2>&1"); err != nil && fileExists(swapFilePath) {
+		return fmt.Errorf("swapoff: %s", lastLine(out))
+	}
+	if err := os.WriteFile("/etc/fstab", []byte(fstab), 0644); err != nil {
+		return err
+	}
+	os.Remove(swapFilePath)
+	os.Remove(swapSysctlConf)
+	return nil
+}
+
+// offerSwap предлагает создать swap при нехватке памяти и его отсутствии
+func offerSwap() {
+	meminfo, _ := os.ReadFile("/proc/meminfo")
+	memMB := parseMeminfoMB(string(meminfo), "MemTotal")
+	if checkSwap(memMB, parseMeminfoMB(string(meminfo), "SwapTotal")).Status == checkPass {
+		return
+	}
+	size := swapSizeMB(memMB, diskFreeMB("/"))
+	if size == 0 {
+		ui.PrintWarning("Недостаточно места на диске для swap-файла")
+		return
+	}
+	if !ui.ConfirmPrompt(fmt.Sprintf("Создать swap-файл %s на %d МБ (защита от OOM при сборке)?", swapFilePath, size), true) {
+		return
+	}
+	err := ui.RunWithSpinner("Создание swap-файла...", func() error {
+		return createSwapFile(size)
+	})
+	if err != nil {
+		ui.PrintWarning("Swap не создан: " + err.Error())
+		return
+	}
+	ui.PrintSuccess(fmt.Sprintf("Swap %d МБ включён (vm.swappiness=%d)", size, swapSwappiness))
+}
+
+// offerSwapRemoval — при удалении бота: убрать swap, если его создал установщик
+func offerSwapRemoval() {
+	if !installerSwapActive() || !ui.ConfirmPrompt("Удалить swap-файл "+swapFilePath+", созданный установщиком?", false) {
+		return
+	}
+	if err := removeSwapFile(); err != nil {
+		ui.PrintWarning("Swap не удалён: " + err.Error())
+		return
+	}
+	ui.PrintSuccess("Swap-файл удалён")
+}
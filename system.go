@@ -25,11 +25,12 @@ func detectOS() string {
 		globalProgress.info("ОС: " + prettyName)
 	}
 	switch out {
-	case "ubuntu", "debian":
+	case "ubuntu", "debian", "rocky", "almalinux", "centos", "rhel", "fedora", "alpine":
+		globalProgress.info("Пакетный менеджер: " + pkgManager().Name)
 		return out
 	default:
 		if out != "" {
-			globalProgress.warn("Оптимизировано для Ubuntu/Debian. Обнаружено: " + out)
+			globalProgress.warn("Поддерживаются Ubuntu/Debian, Rocky/Alma/Fedora и Alpine. Обнаружено: " + out)
 			if !ui.ConfirmPrompt("Продолжить на неподдерживаемой ОС?", false) {
 				os.Exit(0)
 			}
@@ -43,30 +44,46 @@ func detectOS() string {
 // ════════════════════════════════════════════════════════════════
 
 func updateSystem() {
-	runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get update -y -qq 2>/dev/null")
+	pkgManager().Update()
 }
 
 func installBasePackages() {
-	packages := []string{
-		"curl wget git",
-		"nano htop",
-		"make openssl ca-certificates gnupg",
-		"lsb-release dnsutils",
+	pm := pkgManager()
+	if pm.rhelFamily() {
+		// htop и certbot на RHEL-клонах — из EPEL (на Fedora пакета нет, ошибка игнорируется)
+		pm.Install("epel-release")
 	}
-	for _, pkg := range packages {
-		runShellSilent(fmt.Sprintf("DEBIAN_FRONTEND=noninteractive apt-get install -y -qq %s 2>/dev/null || true", pkg))
+	packages := [][]string{
+		{"curl", "wget", "git"},
+		{"nano", "htop"},
+		{"make", "openssl", "ca-certificates", "gnupg"},
+		{"lsb-release", "dnsutils"},
+		{"certbot", "certbot-nginx"},
+	}
+	for _, pkgs := range packages {
+		pm.Install(pkgs...)
 	}
-	runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get install -y -qq certbot python3-certbot-nginx 2>/dev/null || true")
 }
 
-func installDocker() {
-	if commandExists("docker") {
+func installDocker(cfg *Config) {
+	fresh := !commandExists("docker")
+	if !fresh {
 		ver, _ := runShellSilent("docker --version")
 		globalProgress.done("Docker: " + ver)
 	} else {
-		runShellSilent("DEBIAN_FRONTEND=noninteractive curl -fsSL https://get.docker.com | sh")
-		runShellSilent("systemctl enable docker 2>/dev/null || true")
-		runShellSilent("systemctl start docker 2>/dev/null || true")
+		pm := pkgManager()
+		switch {
+		case pm.Name == "apk":
+			pm.Install("docker", "docker-cli-compose")
+		case pm.rhelFamily():
+			installDockerRPM(pm, dockerRepoBase(cfg))
+		case cfg.DockerDownloadURL != "":
+			installDockerFromMirror(cfg.DockerDownloadURL)
+		default:
+			runShellSilent("DEBIAN_FRONTEND=noninteractive curl -fsSL https://get.docker.com | sh")
+		}
+		runShellSilent(serviceCmd("enable", "docker") + " 2>/dev/null || true")
+		runShellSilent(serviceCmd("start", "docker") + " 2>/dev/null || true")
 
 		if !commandExists("docker") {
 			globalProgress.fail("Не удалось установить Docker!")
@@ -76,6 +93,7 @@ func installDocker() {
 		ver, _ := runShellSilent("docker --version")
 		globalProgress.done("Docker установлен: " + ver)
 	}
+	configureDockerDaemon(cfg, fresh)
 
 	if out, err := runShellSilent("docker compose version 2>/dev/null"); err == nil && out != "" {
 		globalProgress.done("Docker Compose: " + out)
@@ -83,33 +101,61 @@ func installDocker() {
 		globalProgress.done("Docker Compose (standalone): " + out)
 	} else {
 		globalProgress.fail("Docker Compose не найден!")
-		globalProgress.info("Установите Docker Compose: apt install docker-compose-plugin")
+		globalProgress.info("Установите Docker Compose: " + pkgManager().installCmd("docker-compose-plugin"))
 		os.Exit(1)
 	}
 }
 
+// installDockerRPM — Docker CE из репозитория download.docker.com (или зеркала) для dnf/yum
+func installDockerRPM(pm *packageManager, base string) {
+	distro := "centos"
+	if id, _ := runShellSilent(". /etc/os-release && echo $ID"); id == "fedora" || id == "rhel" {
+		distro = id
+	}
+	runShellSilent(fmt.Sprintf("curl -fsSL %s/linux/%s/docker-ce.repo -o /etc/yum.repos.d/docker-ce.repo", base, distro))
+	if base != dockerDownloadBase {
+		runShellSilent(fmt.Sprintf("sed -i 's#%s#%s#g' /etc/yum.repos.d/docker-ce.repo", dockerDownloadBase, base))
+	}
+	pm.Install("docker-ce", "docker-ce-cli", "containerd.io", "docker-compose-plugin")
+}
+
 func installNginx() {
 	if commandExists("nginx") {
 		return
 	}
 	ui.RunWithSpinner("Установка Nginx...", func() error {
-		runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get install -y -qq nginx")
-		runShellSilent("systemctl enable nginx")
-		runShellSilent("systemctl start nginx")
+		pkgManager().Install("nginx")
+		runShellSilent(serviceCmd("enable", "nginx"))
+		runShellSilent(serviceCmd("start", "nginx"))
 		return nil
 	})
 }
 
+// installCaddy — curl и пакетный менеджер идут через прокси из applyNetworkEnv/configureApt
 func installCaddy() {
 	if commandExists("caddy") {
 		return
 	}
 	ui.RunWithSpinner("Установка Caddy...", func() error {
-		runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get install -y -qq debian-keyring debian-archive-keyring apt-transport-https curl")
-		runShellSilent("curl -1sLf 'https://dl.cloudsmith.io/public/caddy/stable/gpg.key' | gpg --dearmor -o /usr/share/keyrings/caddy-stable-archive-keyring.gpg 2>/dev/null || true")
-		runShellSilent("curl -1sLf 'https://dl.cloudsmith.io/public/caddy/stable/debian.deb.txt' | tee /etc/apt/sources.list.d/caddy-stable.list > /dev/null")
-		runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get update -y -qq")
-		runShellSilent("DEBIAN_FRONTEND=noninteractive apt-get install -y -qq caddy")
+		pm := pkgManager()
+		switch {
+		case pm.Name == "apk":
+			pm.Install("caddy")
+		case pm.rhelFamily():
+			plugin := "dnf-plugins-core"
+			if pm.Name == "yum" {
+				plugin = "yum-plugin-copr"
+			}
+			pm.Install(plugin)
+			runShellSilent(pm.Name + " copr enable -y @caddy/caddy")
+			pm.Install("caddy")
+		default:
+			pm.Install("debian-keyring", "debian-archive-keyring", "apt-transport-https", "curl")
+			runShellSilent("curl -1sLf 'https://dl.cloudsmith.io/public/caddy/stable/gpg.key' | gpg --dearmor -o /usr/share/keyrings/caddy-stable-archive-keyring.gpg 2>/dev/null || true")
+			runShellSilent("curl -1sLf 'https://dl.cloudsmith.io/public/caddy/stable/debian.deb.txt' | tee /etc/apt/sources.list.d/caddy-stable.list > /dev/null")
+			pm.Update()
+			pm.Install("caddy")
+		}
 		return nil
 	})
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// TELEGRAM BOT API
+// ════════════════════════════════════════════════════════════════
+
+const (
+	defaultTelegramAPIBase = "https://api.telegram.org"
+	defaultWebhookPath     = "/webhook"
+	telegramCallTimeout    = 15 * time.Second
+)
+
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// telegramAPIError — Bot API ответил ошибкой (в отличие от сетевой ошибки)
+type telegramAPIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *telegramAPIError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, e.Description)
+}
+
+// telegramBot — результат getMe
+type telegramBot struct {
+	ID        int64  `json:"id"`
+	IsBot     bool   `json:"is_bot"`
+	FirstName string `json:"first_name"`
+	Username  string `json:"username"`
+}
+
+// telegramAPIBase — адрес Bot API; TELEGRAM_API_URL задаёт свой сервер
+// (локальный telegram-bot-api или заглушку для тестов)
+func telegramAPIBase() string {
+	return strings.TrimRight(orDefault(os.Getenv("TELEGRAM_API_URL"), defaultTelegramAPIBase), "/")
+}
+
+// telegramCall вызывает метод Bot API; прокси берётся из окружения (HTTPS_PROXY)
+func telegramCall(token, method string, params url.Values) (json.RawMessage, error) {
+	client := &http.Client{Timeout: telegramCallTimeout}
+	resp, err := client.PostForm(fmt.Sprintf("%s/bot%s/%s", telegramAPIBase(), token, method), params)
+	if err != nil {
+		// в тексте ошибки net/http есть полный URL вместе с токеном
+		return nil, fmt.Errorf("%s: %s", method, strings.ReplaceAll(err.Error(), token, "***"))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	var r telegramResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("%s: HTTP %d", method, resp.StatusCode)
+	}
+	if !r.OK {
+		return nil, &telegramAPIError{Method: method, Code: r.ErrorCode, Description: r.Description}
+	}
+	return r.Result, nil
+}
+
+// validBotToken проверяет формат токена без обращения к API: строка целиком — токен
+func validBotToken(token string) bool {
+	return token != "" && ui.BotTokenRe.FindString(token) == token
+}
+
+// telegramGetMe проверяет токен и возвращает данные бота
+func telegramGetMe(token string) (*telegramBot, error) {
+	raw, err := telegramCall(token, "getMe", nil)
+	if err != nil {
+		return nil, err
+	}
+	var bot telegramBot
+	if err := json.Unmarshal(raw, &bot); err != nil {
+		return nil, fmt.Errorf("getMe: неожиданный ответ")
+	}
+	return &bot, nil
+}
+
+func (b *telegramBot) String() string {
+	if b.Username == "" {
+		return b.FirstName
+	}
+	return fmt.Sprintf("@%s (%s)", b.Username, b.FirstName)
+}
+
+// telegramWebhookURL — адрес, который бот регистрирует в Telegram: WEBHOOK_URL + WEBHOOK_PATH
+func telegramWebhookURL(env map[string]string) string {
+	base := strings.TrimRight(env["WEBHOOK_URL"], "/")
+	if base == "" {
+		return ""
+	}
+	path := orDefault(env["WEBHOOK_PATH"], defaultWebhookPath)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return base + path
+}
+
+func setTelegramWebhook(token, webhookURL, secret string) error {
+	params := url.Values{"url": {webhookURL}}
+	if secret != "" {
+		params.Set("secret_token", secret)
+	}
+	_, err := telegramCall(token, "setWebhook", params)
+	return err
+}
+
+// telegramWebhookInfo — результат getWebhookInfo
+type telegramWebhookInfo struct {
+	URL                  string `json:"url"`
+	PendingUpdateCount   int    `json:"pending_update_count"`
+	IPAddress            string `json:"ip_address"`
+	LastErrorDate        int64  `json:"last_error_date"`
+	LastErrorMessage     string `json:"last_error_message"`
+	MaxConnections       int    `json:"max_connections"`
+	HasCustomCertificate bool   `json:"has_custom_certificate"`
+}
+
+func getTelegramWebhookInfo(token string) (*telegramWebhookInfo, error) {
+	raw, err := telegramCall(token, "getWebhookInfo", nil)
+	if err != nil {
+		return nil, err
+	}
+	var info telegramWebhookInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return nil, fmt.Errorf("getWebhookInfo: неожиданный ответ")
+	}
+	return &info, nil
+}
+
+func deleteTelegramWebhook(token string, dropPending bool) error {
+	params := url.Values{}
+	if dropPending {
+		params.Set("drop_pending_updates", "true")
+	}
+	_, err := telegramCall(token, "deleteWebhook", params)
+	return err
+}
@@ -48,18 +48,29 @@ func runShell(command string) error {
 }
 
 func runShellSilent(command string) (string, error) {
+	return runShellSilentEnv(command)
+}
+
+// runShellSilentEnv — runShellSilent с дополнительными переменными окружения
+// только для этой команды (пароли не попадают в окружение установщика)
+func runShellSilentEnv(command string, env ...string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, "bash", "-c", command)
 	cmd.Dir = "/root"
-	cmd.Env = append(os.Environ(), "DEBIAN_FRONTEND=noninteractive")
+	cmd.Env = append(append(os.Environ(), "DEBIAN_FRONTEND=noninteractive"), env...)
 	out, err := cmd.CombinedOutput()
 	if ctx.Err() == context.DeadlineExceeded {
-		return "", fmt.Errorf("команда превысила таймаут: %s", command)
+		return "", fmt.Errorf("команда превысила таймаут: %s", ui.Redact(command))
 	}
 	return strings.TrimSpace(string(out)), err
 }
 
+// shellQuote экранирует значение для bash -c (одинарные кавычки)
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 func commandExists(name string) bool {
 	_, err := exec.LookPath(name)
 	return err == nil
@@ -81,6 +92,13 @@ func generateSafePassword(length int) string {
 	return string(b)
 }
 
+func orDefault(val, def string) string {
+	if val == "" {
+		return def
+	}
+	return val
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bedolaga-installer/pkg/ui"
+)
+
+// ════════════════════════════════════════════════════════════════
+// MANAGE: WEBHOOK
+// ════════════════════════════════════════════════════════════════
+
+const webhookUsage = "Использование: bot webhook status | set [url] | delete [--drop-pending] | test"
+
+// syntheticUpdate — тестовое обновление для bot webhook test: опрос, который
+// бот не отправлял, — обработчиков нет, в БД и чаты ничего не пишется
+func syntheticUpdate(id int64) []byte {
+	return []byte(fmt.Sprintf(`{"update_id":%d,"poll":{"id":"bedolaga-installer-test","question":"webhook test",`+
+		`"options":[{"text":"ok","voter_count":0}],"total_voter_count":0,"is_closed":true,"is_anonymous":true,`+
+		`"type":"regular","allows_multiple_answers":false}}`, id))
+}
+
+// postWebhookUpdate отправляет обновление на публичный адрес вебхука так же,
+// как это делает Telegram, и возвращает HTTP-код ответа бота
+func postWebhookUpdate(hookURL, secret string, body []byte) (int, error) {
+	req, err := http.NewRequest("POST", hookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+	}
+	resp, err := (&http.Client{Timeout: telegramCallTimeout}).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func manageWebhook(installDir string, args []string) {
+	env := readEnvFile(filepath.Join(installDir, ".env"))
+	token := env["BOT_TOKEN"]
+	if token == "" {
+		ui.PrintError("BOT_TOKEN в .env не задан")
+		return
+	}
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	hookURL := telegramWebhookURL(env)
+
+	switch action {
+	case "status", "info":
+		webhookStatus(token, hookURL, env["BOT_RUN_MODE"])
+	case "set":
+		if len(args) > 1 {
+			hookURL = args[1]
+		}
+		if !strings.HasPrefix(hookURL, "https://") {
+			ui.PrintError("Нужен HTTPS-адрес вебхука: задайте WEBHOOK_URL в .env или bot webhook set https://...")
+			return
+		}
+		if env["BOT_RUN_MODE"] != "webhook" {
+			ui.PrintWarning("BOT_RUN_MODE=" + orDefault(env["BOT_RUN_MODE"], "polling") + " — бот в режиме polling снимет вебхук при запуске")
+		}
+		if err := setTelegramWebhook(token, hookURL, env["WEBHOOK_SECRET_TOKEN"]); err != nil {
+			ui.PrintError(err.Error())
+			return
+		}
+		ui.PrintSuccess("Вебхук зарегистрирован: " + hookURL)
+	case "delete", "remove":
+		drop := containsString(args, "--drop-pending")
+		if err := deleteTelegramWebhook(token, drop); err != nil {
+			ui.PrintError(err.Error())
+			return
+		}
+		ui.PrintSuccess("Вебхук удалён")
+		if drop {
+			ui.PrintDim("Накопленные обновления сброшены")
+		}
+	case "test":
+		webhookTest(hookURL, env["WEBHOOK_SECRET_TOKEN"])
+	default:
+		ui.PrintError("Неизвестное действие: " + action)
+		ui.PrintDim(webhookUsage)
+	}
+}
+
+func webhookStatus(token, expected, runMode string) {
+	fmt.Println()
+	fmt.Println(ui.AccentBar.Render("  ВЕБХУК TELEGRAM"))
+	fmt.Println()
+
+	info, err := getTelegramWebhookInfo(token)
+	if err != nil {
+		ui.PrintError(err.Error())
+		return
+	}
+	ui.PrintInfo("Режим бота: " + orDefault(runMode, "polling"))
+	switch {
+	case info.URL == "":
+		ui.PrintWarning("Вебхук не зарегистрирован")
+	case expected != "" && info.URL != expected:
+		ui.PrintWarning("Зарегистрирован другой адрес: " + info.URL)
+		ui.PrintDim("Ожидается " + expected + " — исправить: bot webhook set")
+	default:
+		ui.PrintSuccess("URL: " + info.URL)
+	}
+	if info.IPAddress != "" {
+		ui.PrintInfo("IP, на который отправляет Telegram: " + info.IPAddress)
+	}
+	if info.PendingUpdateCount > 0 {
+		ui.PrintWarning(fmt.Sprintf("Необработанных обновлений: %d", info.PendingUpdateCount))
+	} else {
+		ui.PrintSuccess("Необработанных обновлений нет")
+	}
+	if info.LastErrorDate > 0 {
+		when := time.Unix(info.LastErrorDate, 0).Format("2006-01-02 15:04:05")
+		ui.PrintError(fmt.Sprintf("Последняя ошибка (%s): %s", when, info.LastErrorMessage))
+	}
+	if runMode == "webhook" && info.URL == "" {
+		ui.PrintDim("Зарегистрировать: bot webhook set")
+	}
+}
+
+// webhookTest проверяет, что обновление через публичный адрес доходит до бота,
+// а запрос с чужим секретом отклоняется
+func webhookTest(hookURL, secret string) {
+	if hookURL == "" {
+		ui.PrintError("WEBHOOK_URL не задан в .env")
+		return
+	}
+	var code int
+	err := ui.RunWithSpinner("Отправка тестового обновления на "+hookURL+"...", func() error {
+		var err error
+		code, err = postWebhookUpdate(hookURL, secret, syntheticUpdate(time.Now().Unix()))
+		return err
+	})
+	if err != nil {
+		ui.PrintError("Адрес вебхука недоступен: " + err.Error())
+		return
+	}
+	if code != http.StatusOK {
+		ui.PrintError(fmt.Sprintf("Бот ответил HTTP %d — проверьте прокси и WEBHOOK_PATH", code))
+		return
+	}
+	ui.PrintSuccess("Бот принял обновление (HTTP 200)")
+
+	if secret == "" {
+		ui.PrintWarning("WEBHOOK_SECRET_TOKEN не задан — запросы к вебхуку не проверяются")
+		return
+	}
+	code, err = postWebhookUpdate(hookURL, "invalid-"+generateToken()[:8], syntheticUpdate(time.Now().Unix()+1))
+	if err == nil && code == http.StatusOK {
+		ui.PrintWarning("Запрос с неверным секретом тоже принят — бот не проверяет WEBHOOK_SECRET_TOKEN")
+		return
+	}
+	ui.PrintSuccess("Запрос с неверным секретом отклонён")
+}